@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -126,12 +127,11 @@ var delProxyCmd = &cobra.Command{
 
 		fc := fastcaddy.New()
 
-		if !fc.HasID(routeID) {
-			return fmt.Errorf("路由 ID '%s' 不存在", routeID)
-		}
-
 		fmt.Printf("正在删除路由: %s\n", routeID)
 		err := fc.DeleteRoute(routeID)
+		if errors.Is(err, fastcaddy.ErrNotFound) {
+			return fmt.Errorf("路由 ID '%s' 不存在", routeID)
+		}
 		if err != nil {
 			return fmt.Errorf("删除路由失败: %w", err)
 		}
@@ -0,0 +1,27 @@
+package fastcaddy
+
+import (
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/routes"
+)
+
+// APIError Caddy API 返回非 2xx 状态码时的结构化错误
+// 使用 errors.As(err, &apiErr) 获取状态码、响应体和请求路径；Module 不为空时表示 Caddy 未安装该模块
+type APIError = api.APIError
+
+// ValidationError 配置校验失败时的错误，包含出错的行列或字段
+type ValidationError = api.ValidationError
+
+// ConflictError 条件更新时配置已被其他客户端修改 (Etag 不匹配)
+// SetConfigPathIfMatch 返回该错误时，应重新读取配置和 Etag 后重试
+type ConflictError = api.ConflictError
+
+// LoadError 通过 /load 加载配置失败时的错误，包含 Caddy 的错误信息及其在提交配置中的偏移量和行列
+type LoadError = api.LoadError
+
+// BatchError 批量添加路由失败时的错误，Index 指出导致失败的路由
+type BatchError = routes.BatchError
+
+// ErrNotFound 路由 ID 或配置路径不存在
+// 例如 DeleteRoute 删除不存在的路由时，errors.Is(err, ErrNotFound) 为 true
+var ErrNotFound = api.ErrNotFound
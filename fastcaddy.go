@@ -1,15 +1,20 @@
 package fastcaddy
 
 import (
+	"context"
+
 	"github.com/OrbitDeploy/fastcaddy/internal/api"
 	"github.com/OrbitDeploy/fastcaddy/internal/config"
 	"github.com/OrbitDeploy/fastcaddy/internal/routes"
 	"github.com/OrbitDeploy/fastcaddy/internal/tls"
 	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
 )
 
 // FastCaddy 主要客户端 - 提供 Caddy 配置管理的统一接口
 // 这是主要的入口点，整合了所有功能模块
+// 可以在多个 goroutine 中并发使用：所有管理器共享同一个客户端，
+// 需要先读取配置再写回的操作由客户端的配置锁串行执行，不会相互覆盖
 type FastCaddy struct {
 	API    *api.Client     // API 客户端
 	Config *config.Manager // 配置管理器
@@ -19,21 +24,60 @@ type FastCaddy struct {
 
 // New 创建新的 FastCaddy 客户端实例
 func New() *FastCaddy {
+	return newWithClient(api.NewClient())
+}
+
+// NewWithOptions 使用指定选项创建 FastCaddy 客户端实例
+// 例如 WithUnixSocket、WithSSHClient，选项无效时返回错误
+func NewWithOptions(opts ...Option) (*FastCaddy, error) {
+	var o api.Options
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	client, err := api.NewClientWithOptions(o)
+	if err != nil {
+		return nil, err
+	}
+	return newWithClient(client), nil
+}
+
+// newWithClient 基于同一个 API 客户端组装所有功能模块
+func newWithClient(client *api.Client) *FastCaddy {
 	return &FastCaddy{
-		API:    api.NewClient(),
-		Config: config.NewManager(),
-		TLS:    tls.NewManager(),
-		Routes: routes.NewManager(),
+		API:    client,
+		Config: config.NewManagerWithClient(client),
+		TLS:    tls.NewManagerWithClient(client),
+		Routes: routes.NewManagerWithClient(client),
 	}
 }
 
+// Ping 检查 Caddy 管理 API 是否可达 - 便利方法
+// 适合在批量修改配置前或建立 SSH 连接后立即调用，尽早发现问题
+func (fc *FastCaddy) Ping(ctx context.Context) error {
+	return fc.API.Ping(ctx)
+}
+
+// StreamLogs 持续接收 Caddy 的日志，每收到一条 JSON 日志调用一次 handler - 便利方法
+// 通过 Caddy 的 net 日志输出发送到本库在 Caddy 所在主机上监听的端口（配置了 SSH 时经由 SSH 转发），
+// 直到 ctx 被取消；结束时删除临时添加的日志配置
+func (fc *FastCaddy) StreamLogs(ctx context.Context, handler func(event []byte)) error {
+	return fc.Routes.StreamLogs(ctx, handler)
+}
+
 // SetupCaddy 设置 Caddy 基本配置 - 对应 Python 的 setup_caddy 函数
 // 这是初始化 Caddy 配置的主要函数，包括 SSL 配置和 HTTP 应用骨架
 func (fc *FastCaddy) SetupCaddy(cfToken, serverName string, local bool, installTrust *bool) error {
+	return fc.SetupCaddyContext(context.Background(), cfToken, serverName, local, installTrust)
+}
+
+// SetupCaddyContext 设置 Caddy 基本配置（支持 context 取消和超时）
+func (fc *FastCaddy) SetupCaddyContext(ctx context.Context, cfToken, serverName string, local bool, installTrust *bool) error {
 	// 根据环境设置 TLS 配置
 	if local {
 		// 本地开发环境：使用内部证书
-		if err := fc.TLS.AddTLSInternalConfig(); err != nil {
+		if err := fc.TLS.AddTLSInternalConfigContext(ctx); err != nil {
 			return err
 		}
 	} else {
@@ -42,22 +86,120 @@ func (fc *FastCaddy) SetupCaddy(cfToken, serverName string, local bool, installT
 			cfToken = utils.GetCloudflareToken()
 		}
 		if cfToken != "" {
-			if err := fc.TLS.AddACMEConfig(cfToken); err != nil {
+			if err := fc.TLS.AddACMEConfigContext(ctx, cfToken); err != nil {
 				return err
 			}
 		}
 	}
 
 	// 设置 PKI 信任配置
-	if err := fc.TLS.SetupPKITrust(installTrust); err != nil {
+	if err := fc.TLS.SetupPKITrustContext(ctx, installTrust); err != nil {
 		return err
 	}
 
 	// 初始化路由配置
 	if serverName == "" {
-		serverName = "srv0" // 默认服务器名
+		serverName = routes.DefaultServer // 默认服务器名
+	}
+	return fc.Routes.InitRoutesContext(ctx, serverName, 1)
+}
+
+// EnableMetrics 开启 HTTP 服务器的请求指标收集 - 便利方法
+func (fc *FastCaddy) EnableMetrics() error {
+	return fc.Config.EnableMetrics()
+}
+
+// EnableMetricsContext 开启 HTTP 服务器的请求指标收集（支持 context 取消和超时）
+func (fc *FastCaddy) EnableMetricsContext(ctx context.Context) error {
+	return fc.Config.EnableMetricsContext(ctx)
+}
+
+// GetMetrics 获取 Prometheus 文本格式的指标 - 便利方法
+// 返回原始文本，可直接交给 Prometheus 解析器
+func (fc *FastCaddy) GetMetrics(ctx context.Context) ([]byte, error) {
+	return fc.Config.GetMetrics(ctx)
+}
+
+// ACMEIssuer 创建 ACME 证书颁发者 - 便利方法
+// 可指定自建 ACME CA 的目录 URL、账号邮箱和外部账号绑定 (如 ZeroSSL 的 EAB 凭据)
+func ACMEIssuer(ca, email string, eab *types.ExternalAccount) (types.TLSIssuer, error) {
+	return tls.ACMEIssuer(ca, email, eab)
+}
+
+// AddIssuerPolicy 为指定主机名设置证书颁发者 - 便利方法
+// 只有列出的主机名使用这些颁发者，其他主机名保持默认；已有相同主机名的策略时只替换其颁发者
+func (fc *FastCaddy) AddIssuerPolicy(subjects []string, issuers ...types.TLSIssuer) error {
+	return fc.TLS.AddIssuerPolicy(subjects, issuers...)
+}
+
+// AddIssuerPolicyContext 为指定主机名设置证书颁发者（支持 context 取消和超时）
+func (fc *FastCaddy) AddIssuerPolicyContext(ctx context.Context, subjects []string, issuers ...types.TLSIssuer) error {
+	return fc.TLS.AddIssuerPolicyContext(ctx, subjects, issuers...)
+}
+
+// EnableOnDemandTLS 配置按需 TLS 的授权端点和频率限制 - 便利方法
+// 还需要通过 EnableOnDemandTLSForRoute 或 EnableOnDemandForHosts 指定哪些主机名允许按需申请证书
+func (fc *FastCaddy) EnableOnDemandTLS(askURL string, rateLimit types.OnDemandRateLimit) error {
+	return fc.TLS.EnableOnDemandTLS(askURL, rateLimit)
+}
+
+// EnableOnDemandTLSContext 配置按需 TLS 的授权端点和频率限制（支持 context 取消和超时）
+func (fc *FastCaddy) EnableOnDemandTLSContext(ctx context.Context, askURL string, rateLimit types.OnDemandRateLimit) error {
+	return fc.TLS.EnableOnDemandTLSContext(ctx, askURL, rateLimit)
+}
+
+// CheckAskEndpoint 检查 Caddy 所在主机能否连接到按需 TLS 授权端点 - 便利方法
+func (fc *FastCaddy) CheckAskEndpoint(ctx context.Context, askURL string) error {
+	return fc.TLS.CheckAskEndpoint(ctx, askURL)
+}
+
+// EnableOnDemandForHosts 允许为指定主机名按需申请证书 - 便利方法
+// hosts 为空时对所有未单独配置策略的主机名开启
+func (fc *FastCaddy) EnableOnDemandForHosts(hosts ...string) error {
+	return fc.TLS.EnableOnDemandForHosts(hosts...)
+}
+
+// EnableOnDemandForHostsContext 允许为指定主机名按需申请证书（支持 context 取消和超时）
+func (fc *FastCaddy) EnableOnDemandForHostsContext(ctx context.Context, hosts ...string) error {
+	return fc.TLS.EnableOnDemandForHostsContext(ctx, hosts...)
+}
+
+// EnableOnDemandTLSForRoute 允许为路由匹配的主机名按需申请证书 - 便利方法
+// 路由没有主机名匹配条件时（如承接所有客户域名的路由）对不限主机名的策略开启
+func (fc *FastCaddy) EnableOnDemandTLSForRoute(routeID string) error {
+	return fc.EnableOnDemandTLSForRouteContext(context.Background(), routeID)
+}
+
+// EnableOnDemandTLSForRouteContext 允许为路由匹配的主机名按需申请证书（支持 context 取消和超时）
+func (fc *FastCaddy) EnableOnDemandTLSForRouteContext(ctx context.Context, routeID string) error {
+	hosts, err := fc.Routes.RouteHosts(ctx, routeID)
+	if err != nil {
+		return err
 	}
-	return fc.Routes.InitRoutes(serverName, 1)
+	return fc.TLS.EnableOnDemandForHostsContext(ctx, hosts...)
+}
+
+// WaitForCert 等待主机名的 TLS 证书就绪 - 便利方法
+// 添加启用自动 HTTPS 的路由后调用，阻塞直到 Caddy 提供有效证书或 context 结束
+func (fc *FastCaddy) WaitForCert(ctx context.Context, hostname string) error {
+	return fc.TLS.WaitForCert(ctx, hostname)
+}
+
+// LoadPEMCertificate 加载自有 CA 签发的证书和私钥 - 便利方法
+// 相同证书已加载时不会报错，只补充缺少的标签；tags 可用于在连接策略中引用该证书
+func (fc *FastCaddy) LoadPEMCertificate(certPEM, keyPEM []byte, tags ...string) error {
+	return fc.TLS.LoadPEMCertificate(certPEM, keyPEM, tags...)
+}
+
+// LoadPEMCertificateContext 加载证书和私钥（支持 context 取消和超时）
+func (fc *FastCaddy) LoadPEMCertificateContext(ctx context.Context, certPEM, keyPEM []byte, tags ...string) error {
+	return fc.TLS.LoadPEMCertificateContext(ctx, certPEM, keyPEM, tags...)
+}
+
+// NewReverseProxy 创建反向代理处理器构建器 - 便利方法
+// 例如 NewReverseProxy().Upstream("localhost:8080").FlushInterval(-1).Build()
+func NewReverseProxy() *routes.ReverseProxyBuilder {
+	return routes.NewReverseProxy()
 }
 
 // AddReverseProxy 添加反向代理 - 便利方法
@@ -66,40 +208,502 @@ func (fc *FastCaddy) AddReverseProxy(fromHost, toURL string) error {
 	return fc.Routes.AddReverseProxy(fromHost, toURL)
 }
 
+// AddReverseProxyContext 添加反向代理（支持 context 取消和超时）
+func (fc *FastCaddy) AddReverseProxyContext(ctx context.Context, fromHost, toURL string) error {
+	return fc.Routes.AddReverseProxyContext(ctx, fromHost, toURL)
+}
+
+// AddRoutes 在一次请求中添加多个路由 - 便利方法
+// 全部生效或全部不生效；失败时返回的 *BatchError 指出导致失败的路由
+// 可混合传入 types.Route 和 types.RouteConfig
+func (fc *FastCaddy) AddRoutes(routes ...types.RouteDefinition) error {
+	return fc.Routes.AddRoutes(routes...)
+}
+
+// AddRoutesContext 在一次请求中添加多个路由（支持 context 取消和超时）
+func (fc *FastCaddy) AddRoutesContext(ctx context.Context, routes ...types.RouteDefinition) error {
+	return fc.Routes.AddRoutesContext(ctx, routes...)
+}
+
+// AddRoute 添加路由到默认服务器 - 便利方法
+// 接受类型化的 types.Route，或 GetRoute 返回的 types.RouteConfig（可修改后直接传入）
+func (fc *FastCaddy) AddRoute(route types.RouteDefinition) error {
+	return fc.Routes.AddRoute(route)
+}
+
+// AddRouteContext 添加路由到默认服务器（支持 context 取消和超时）
+func (fc *FastCaddy) AddRouteContext(ctx context.Context, route types.RouteDefinition) error {
+	return fc.Routes.AddRouteContext(ctx, route)
+}
+
+// NewRouteConfig 将类型化的 Route 转换为保留原始 JSON 的 RouteConfig
+func NewRouteConfig(route types.Route) (types.RouteConfig, error) {
+	return types.NewRouteConfig(route)
+}
+
+// AddRouteToServer 将路由添加到指定服务器 - 便利方法
+// 适用于 apps/http/servers 下定义了多个服务器的情况，服务器不存在时返回错误
+func (fc *FastCaddy) AddRouteToServer(serverName string, route types.RouteDefinition) error {
+	return fc.Routes.AddRouteToServer(serverName, route)
+}
+
+// AddRouteToServerContext 将路由添加到指定服务器（支持 context 取消和超时）
+func (fc *FastCaddy) AddRouteToServerContext(ctx context.Context, serverName string, route types.RouteDefinition) error {
+	return fc.Routes.AddRouteToServerContext(ctx, serverName, route)
+}
+
+// SetAutomaticHTTPS 设置服务器的自动 HTTPS 选项 - 便利方法
+// 可禁用自动 HTTPS 或 HTTP→HTTPS 重定向，或跳过指定主机名
+func (fc *FastCaddy) SetAutomaticHTTPS(serverName string, opts types.AutoHTTPSOptions) error {
+	return fc.Routes.SetAutomaticHTTPS(serverName, opts)
+}
+
+// SetAutomaticHTTPSContext 设置服务器的自动 HTTPS 选项（支持 context 取消和超时）
+func (fc *FastCaddy) SetAutomaticHTTPSContext(ctx context.Context, serverName string, opts types.AutoHTTPSOptions) error {
+	return fc.Routes.SetAutomaticHTTPSContext(ctx, serverName, opts)
+}
+
+// SetListen 设置服务器的监听地址 - 便利方法
+// 地址格式如 ":443"、"127.0.0.1:8080"，格式无效时不会发送请求
+func (fc *FastCaddy) SetListen(serverName string, addrs ...string) error {
+	return fc.Routes.SetListen(serverName, addrs...)
+}
+
+// SetListenContext 设置服务器的监听地址（支持 context 取消和超时）
+func (fc *FastCaddy) SetListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	return fc.Routes.SetListenContext(ctx, serverName, addrs...)
+}
+
+// AddListen 为服务器追加监听地址 - 便利方法
+func (fc *FastCaddy) AddListen(serverName string, addrs ...string) error {
+	return fc.Routes.AddListen(serverName, addrs...)
+}
+
+// AddListenContext 为服务器追加监听地址（支持 context 取消和超时）
+func (fc *FastCaddy) AddListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	return fc.Routes.AddListenContext(ctx, serverName, addrs...)
+}
+
+// RemoveListen 从服务器删除监听地址 - 便利方法
+func (fc *FastCaddy) RemoveListen(serverName string, addrs ...string) error {
+	return fc.Routes.RemoveListen(serverName, addrs...)
+}
+
+// RemoveListenContext 从服务器删除监听地址（支持 context 取消和超时）
+func (fc *FastCaddy) RemoveListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	return fc.Routes.RemoveListenContext(ctx, serverName, addrs...)
+}
+
+// EnableAccessLog 将服务器的访问日志写入按大小滚动的文件 - 便利方法
+// 默认使用 JSON 格式，日志名称默认与服务器名称相同
+func (fc *FastCaddy) EnableAccessLog(serverName string, opts types.AccessLogOptions) error {
+	return fc.Routes.EnableAccessLog(serverName, opts)
+}
+
+// EnableAccessLogContext 将服务器的访问日志写入按大小滚动的文件（支持 context 取消和超时）
+func (fc *FastCaddy) EnableAccessLogContext(ctx context.Context, serverName string, opts types.AccessLogOptions) error {
+	return fc.Routes.EnableAccessLogContext(ctx, serverName, opts)
+}
+
+// RequireClientCert 为服务器上的指定主机名启用双向 TLS - 便利方法
+// 客户端必须提供由 caPEM 中的 CA 签发的证书；其他主机名不受影响
+func (fc *FastCaddy) RequireClientCert(serverName string, hosts []string, caPEM []byte) error {
+	return fc.Routes.RequireClientCert(serverName, hosts, caPEM)
+}
+
+// RequireClientCertContext 为服务器上的指定主机名启用双向 TLS（支持 context 取消和超时）
+func (fc *FastCaddy) RequireClientCertContext(ctx context.Context, serverName string, hosts []string, caPEM []byte) error {
+	return fc.Routes.RequireClientCertContext(ctx, serverName, hosts, caPEM)
+}
+
+// AddFileServer 添加静态文件服务路由 - 便利方法
+// root 必须是绝对路径，opts.SPAFallback 可为单页应用设置回退文件
+func (fc *FastCaddy) AddFileServer(matchers []types.RouteMatch, root string, opts types.FileServerOptions) error {
+	return fc.Routes.AddFileServer(matchers, root, opts)
+}
+
+// AddFileServerContext 添加静态文件服务路由（支持 context 取消和超时）
+func (fc *FastCaddy) AddFileServerContext(ctx context.Context, matchers []types.RouteMatch, root string, opts types.FileServerOptions) error {
+	return fc.Routes.AddFileServerContext(ctx, matchers, root, opts)
+}
+
+// AddRedirect 添加重定向路由 - 便利方法
+// 例如 AddRedirect(types.NewMatcherSet(types.MatchHost("www.example.com")), "https://example.com{http.request.uri}", 301)
+func (fc *FastCaddy) AddRedirect(match types.RouteMatch, to string, code int) error {
+	return fc.Routes.AddRedirect(match, to, code)
+}
+
+// AddRedirectContext 添加重定向路由（支持 context 取消和超时）
+func (fc *FastCaddy) AddRedirectContext(ctx context.Context, match types.RouteMatch, to string, code int) error {
+	return fc.Routes.AddRedirectContext(ctx, match, to, code)
+}
+
 // AddWildcardRoute 添加通配符路由 - 便利方法
 // 为指定域名创建通配符子域名路由
 func (fc *FastCaddy) AddWildcardRoute(domain string) error {
 	return fc.Routes.AddWildcardRoute(domain)
 }
 
+// AddWildcardRouteContext 添加通配符路由（支持 context 取消和超时）
+func (fc *FastCaddy) AddWildcardRouteContext(ctx context.Context, domain string) error {
+	return fc.Routes.AddWildcardRouteContext(ctx, domain)
+}
+
 // AddSubReverseProxy 添加子域名反向代理 - 便利方法
 // 为通配符域名下的特定子域名添加反向代理
 func (fc *FastCaddy) AddSubReverseProxy(domain, subdomain string, ports interface{}, host string) error {
 	return fc.Routes.AddSubReverseProxyWithPorts(domain, subdomain, ports, host)
 }
 
+// AddSubReverseProxyContext 添加子域名反向代理（支持 context 取消和超时）
+func (fc *FastCaddy) AddSubReverseProxyContext(ctx context.Context, domain, subdomain string, ports interface{}, host string) error {
+	return fc.Routes.AddSubReverseProxyWithPortsContext(ctx, domain, subdomain, ports, host)
+}
+
+// AddUpstream 向反向代理路由追加上游服务器 - 便利方法
+// 上游已存在时不会重复添加
+func (fc *FastCaddy) AddUpstream(routeID, dial string) error {
+	return fc.Routes.AddUpstream(routeID, dial)
+}
+
+// AddUpstreamContext 向反向代理路由追加上游服务器（支持 context 取消和超时）
+func (fc *FastCaddy) AddUpstreamContext(ctx context.Context, routeID, dial string) error {
+	return fc.Routes.AddUpstreamContext(ctx, routeID, dial)
+}
+
+// RemoveUpstream 从反向代理路由删除上游服务器 - 便利方法
+func (fc *FastCaddy) RemoveUpstream(routeID, dial string) error {
+	return fc.Routes.RemoveUpstream(routeID, dial)
+}
+
+// RemoveUpstreamContext 从反向代理路由删除上游服务器（支持 context 取消和超时）
+func (fc *FastCaddy) RemoveUpstreamContext(ctx context.Context, routeID, dial string) error {
+	return fc.Routes.RemoveUpstreamContext(ctx, routeID, dial)
+}
+
+// GetUpstreams 查询反向代理上游服务器的实时状态 - 便利方法
+func (fc *FastCaddy) GetUpstreams(ctx context.Context) ([]types.UpstreamStatus, error) {
+	return fc.Routes.GetUpstreams(ctx)
+}
+
+// EncodeHandler 创建 encode 压缩处理器 - 便利方法
+// 用于自行组装路由时放在 reverse_proxy 等处理器之前
+func EncodeHandler(opts types.EncodeOptions) (types.Handler, error) {
+	return routes.EncodeHandler(opts)
+}
+
+// EnableEncode 为已有路由启用 gzip/zstd 响应压缩 - 便利方法
+// 路由已启用压缩时不做修改
+func (fc *FastCaddy) EnableEncode(routeID string, opts types.EncodeOptions) error {
+	return fc.Routes.EnableEncode(routeID, opts)
+}
+
+// EnableEncodeContext 为已有路由启用响应压缩（支持 context 取消和超时）
+func (fc *FastCaddy) EnableEncodeContext(ctx context.Context, routeID string, opts types.EncodeOptions) error {
+	return fc.Routes.EnableEncodeContext(ctx, routeID, opts)
+}
+
+// RateLimitHandler 创建 rate_limit 限流处理器 - 便利方法
+// 需要包含 caddy-ratelimit 模块的 Caddy 构建
+func RateLimitHandler(opts types.RateLimitOptions) (types.Handler, error) {
+	return routes.RateLimitHandler(opts)
+}
+
+// EnableRateLimit 为已有路由启用限流 - 便利方法
+// Caddy 未安装 rate_limit 模块时返回明确的错误
+func (fc *FastCaddy) EnableRateLimit(routeID string, opts types.RateLimitOptions) error {
+	return fc.Routes.EnableRateLimit(routeID, opts)
+}
+
+// EnableRateLimitContext 为已有路由启用限流（支持 context 取消和超时）
+func (fc *FastCaddy) EnableRateLimitContext(ctx context.Context, routeID string, opts types.RateLimitOptions) error {
+	return fc.Routes.EnableRateLimitContext(ctx, routeID, opts)
+}
+
+// RequestBodyHandler 创建限制请求体大小的处理器 - 便利方法
+// maxSize 可以是字节数或大小字符串 (如 "10MB")
+func RequestBodyHandler(maxSize interface{}) (types.Handler, error) {
+	return routes.RequestBodyHandler(maxSize)
+}
+
+// EnableRequestBodyLimit 为已有路由限制请求体大小 - 便利方法
+func (fc *FastCaddy) EnableRequestBodyLimit(routeID string, maxSize interface{}) error {
+	return fc.Routes.EnableRequestBodyLimit(routeID, maxSize)
+}
+
+// EnableRequestBodyLimitContext 为已有路由限制请求体大小（支持 context 取消和超时）
+func (fc *FastCaddy) EnableRequestBodyLimitContext(ctx context.Context, routeID string, maxSize interface{}) error {
+	return fc.Routes.EnableRequestBodyLimitContext(ctx, routeID, maxSize)
+}
+
+// NewHeaderDown 创建响应头操作构建器 - 便利方法
+// 例如 NewHeaderDown().Set("X-Frame-Options", "DENY").Delete("Server")
+func NewHeaderDown() *routes.HeaderDownBuilder {
+	return routes.NewHeaderDown()
+}
+
+// WithHeaderDown 为路由加上响应头操作 - 便利方法
+// 路由已有 headers 处理器时合并，不会产生重复的处理器
+func WithHeaderDown(route types.Route, headers *routes.HeaderDownBuilder) (types.Route, error) {
+	return routes.WithHeaderDown(route, headers)
+}
+
+// ApplyHeaderDown 为已有路由设置响应头操作 - 便利方法
+func (fc *FastCaddy) ApplyHeaderDown(routeID string, headers *routes.HeaderDownBuilder) error {
+	return fc.Routes.ApplyHeaderDown(routeID, headers)
+}
+
+// ApplyHeaderDownContext 为已有路由设置响应头操作（支持 context 取消和超时）
+func (fc *FastCaddy) ApplyHeaderDownContext(ctx context.Context, routeID string, headers *routes.HeaderDownBuilder) error {
+	return fc.Routes.ApplyHeaderDownContext(ctx, routeID, headers)
+}
+
+// HashPassword 使用 bcrypt 生成 basic auth 密码哈希 - 便利方法
+func HashPassword(plaintext string) (string, error) {
+	return routes.HashPassword(plaintext)
+}
+
+// WithBasicAuth 为路由加上 basic auth 认证 - 便利方法
+// 认证处理器位于 reverse_proxy 等处理器之前，bcryptHash 可由 HashPassword 生成
+func WithBasicAuth(route types.Route, username, bcryptHash string) (types.Route, error) {
+	return routes.WithBasicAuth(route, username, bcryptHash)
+}
+
+// EnableBasicAuth 为已有路由启用 basic auth 认证 - 便利方法
+// 路由已有认证处理器时合并账号，同名账号的密码被替换
+func (fc *FastCaddy) EnableBasicAuth(routeID, username, bcryptHash string) error {
+	return fc.Routes.EnableBasicAuth(routeID, username, bcryptHash)
+}
+
+// EnableBasicAuthContext 为已有路由启用 basic auth 认证（支持 context 取消和超时）
+func (fc *FastCaddy) EnableBasicAuthContext(ctx context.Context, routeID, username, bcryptHash string) error {
+	return fc.Routes.EnableBasicAuthContext(ctx, routeID, username, bcryptHash)
+}
+
 // DeleteRoute 删除路由 - 便利方法
-// 通过路由 ID 删除特定路由
+// 通过路由 ID 删除特定路由，路由不存在时返回的错误满足 errors.Is(err, ErrNotFound)
 func (fc *FastCaddy) DeleteRoute(id string) error {
 	return fc.Routes.DeleteByID(id)
 }
 
+// DeleteRouteContext 删除路由（支持 context 取消和超时）
+func (fc *FastCaddy) DeleteRouteContext(ctx context.Context, id string) error {
+	return fc.Routes.DeleteByIDContext(ctx, id)
+}
+
+// GetRoute 获取单个路由 - 便利方法
+// 返回的 RouteConfig 保留路由的全部字段，可原样传给 AddRoute
+func (fc *FastCaddy) GetRoute(id string) (types.RouteConfig, error) {
+	return fc.Routes.GetRoute(id)
+}
+
+// GetRouteContext 获取单个路由（支持 context 取消和超时）
+func (fc *FastCaddy) GetRouteContext(ctx context.Context, id string) (types.RouteConfig, error) {
+	return fc.Routes.GetRouteContext(ctx, id)
+}
+
+// RouteExists 检查路由是否存在 - 便利方法
+// 与 HasID 不同，网络错误等非 404 失败会作为错误返回
+func (fc *FastCaddy) RouteExists(id string) (bool, error) {
+	return fc.Routes.RouteExists(id)
+}
+
+// RouteExistsContext 检查路由是否存在（支持 context 取消和超时）
+func (fc *FastCaddy) RouteExistsContext(ctx context.Context, id string) (bool, error) {
+	return fc.Routes.RouteExistsContext(ctx, id)
+}
+
+// ListRouteIDs 列出所有服务器中的路由 @id - 便利方法
+// 包括嵌套在 subroute 中的路由，没有 @id 的路由会被跳过；可用于清理不再需要的路由
+func (fc *FastCaddy) ListRouteIDs() ([]string, error) {
+	return fc.Routes.ListRouteIDs()
+}
+
+// ListRouteIDsContext 列出所有服务器中的路由 @id（支持 context 取消和超时）
+func (fc *FastCaddy) ListRouteIDsContext(ctx context.Context) ([]string, error) {
+	return fc.Routes.ListRouteIDsContext(ctx)
+}
+
+// ListServerRouteIDs 列出指定服务器中的路由 @id - 便利方法
+func (fc *FastCaddy) ListServerRouteIDs(serverName string) ([]string, error) {
+	return fc.Routes.ListServerRouteIDs(serverName)
+}
+
+// ListServerRouteIDsContext 列出指定服务器中的路由 @id（支持 context 取消和超时）
+func (fc *FastCaddy) ListServerRouteIDsContext(ctx context.Context, serverName string) ([]string, error) {
+	return fc.Routes.ListServerRouteIDsContext(ctx, serverName)
+}
+
+// UpsertRoute 添加或替换指定 ID 的路由 - 便利方法
+// 可重复调用，返回 true 表示新建，false 表示替换了已有路由
+func (fc *FastCaddy) UpsertRoute(id string, route types.RouteDefinition) (bool, error) {
+	return fc.Routes.UpsertRoute(id, route)
+}
+
+// UpsertRouteContext 添加或替换指定 ID 的路由（支持 context 取消和超时）
+func (fc *FastCaddy) UpsertRouteContext(ctx context.Context, id string, route types.RouteDefinition) (bool, error) {
+	return fc.Routes.UpsertRouteContext(ctx, id, route)
+}
+
 // HasID 检查 ID 是否存在 - 便利方法
 func (fc *FastCaddy) HasID(id string) bool {
 	return fc.API.HasID(id)
 }
 
+// HasIDContext 检查 ID 是否存在（支持 context 取消和超时）
+func (fc *FastCaddy) HasIDContext(ctx context.Context, id string) bool {
+	return fc.API.HasIDContext(ctx, id)
+}
+
 // HasPath 检查路径是否存在 - 便利方法
 func (fc *FastCaddy) HasPath(path string) bool {
 	return fc.API.HasPath(path)
 }
 
+// HasPathContext 检查路径是否存在（支持 context 取消和超时）
+func (fc *FastCaddy) HasPathContext(ctx context.Context, path string) bool {
+	return fc.API.HasPathContext(ctx, path)
+}
+
 // GetConfig 获取配置 - 便利方法
 func (fc *FastCaddy) GetConfig(path string) (map[string]interface{}, error) {
 	return fc.API.GetConfig(path)
 }
 
+// GetConfigContext 获取配置（支持 context 取消和超时）
+func (fc *FastCaddy) GetConfigContext(ctx context.Context, path string) (map[string]interface{}, error) {
+	return fc.API.GetConfigContext(ctx, path)
+}
+
 // PutConfig 设置配置 - 便利方法
 func (fc *FastCaddy) PutConfig(data interface{}, path, method string) error {
 	return fc.API.PutConfig(data, path, method)
 }
+
+// PutConfigContext 设置配置（支持 context 取消和超时）
+func (fc *FastCaddy) PutConfigContext(ctx context.Context, data interface{}, path, method string) error {
+	return fc.API.PutConfigContext(ctx, data, path, method)
+}
+
+// GetConfigPath 获取任意配置路径的原始 JSON - 便利方法
+func (fc *FastCaddy) GetConfigPath(path string) ([]byte, error) {
+	return fc.Config.GetConfigPath(path)
+}
+
+// GetConfigPathContext 获取任意配置路径的原始 JSON（支持 context 取消和超时）
+func (fc *FastCaddy) GetConfigPathContext(ctx context.Context, path string) ([]byte, error) {
+	return fc.Config.GetConfigPathContext(ctx, path)
+}
+
+// GetConfigIndented 获取格式化后的完整配置 - 便利方法
+func (fc *FastCaddy) GetConfigIndented() ([]byte, error) {
+	return fc.Config.GetConfigIndented()
+}
+
+// GetConfigIndentedContext 获取格式化后的完整配置（支持 context 取消和超时）
+func (fc *FastCaddy) GetConfigIndentedContext(ctx context.Context) ([]byte, error) {
+	return fc.Config.GetConfigIndentedContext(ctx)
+}
+
+// SetConfigPath 替换任意配置路径的值 - 便利方法
+// 只修改该子树，不会覆盖其他位置的并发修改
+func (fc *FastCaddy) SetConfigPath(path string, value interface{}) error {
+	return fc.Config.SetConfigPath(path, value)
+}
+
+// SetConfigPathContext 替换任意配置路径的值（支持 context 取消和超时）
+func (fc *FastCaddy) SetConfigPathContext(ctx context.Context, path string, value interface{}) error {
+	return fc.Config.SetConfigPathContext(ctx, path, value)
+}
+
+// GetConfigPathWithEtag 获取配置路径的原始 JSON 及其 Etag - 便利方法
+func (fc *FastCaddy) GetConfigPathWithEtag(path string) ([]byte, string, error) {
+	return fc.Config.GetConfigPathWithEtag(path)
+}
+
+// GetConfigPathWithEtagContext 获取配置路径的原始 JSON 及其 Etag（支持 context 取消和超时）
+func (fc *FastCaddy) GetConfigPathWithEtagContext(ctx context.Context, path string) ([]byte, string, error) {
+	return fc.Config.GetConfigPathWithEtagContext(ctx, path)
+}
+
+// SetConfigPathIfMatch 仅当配置未被修改时才替换配置路径的值 - 便利方法
+// 配置已被修改时返回 *ConflictError
+func (fc *FastCaddy) SetConfigPathIfMatch(path string, value interface{}, etag string) error {
+	return fc.Config.SetConfigPathIfMatch(path, value, etag)
+}
+
+// SetConfigPathIfMatchContext 仅当配置未被修改时才替换配置路径的值（支持 context 取消和超时）
+func (fc *FastCaddy) SetConfigPathIfMatchContext(ctx context.Context, path string, value interface{}, etag string) error {
+	return fc.Config.SetConfigPathIfMatchContext(ctx, path, value, etag)
+}
+
+// AppendConfigPath 向任意配置路径的数组追加元素 - 便利方法
+func (fc *FastCaddy) AppendConfigPath(path string, value interface{}) error {
+	return fc.Config.AppendConfigPath(path, value)
+}
+
+// AppendConfigPathContext 向任意配置路径的数组追加元素（支持 context 取消和超时）
+func (fc *FastCaddy) AppendConfigPathContext(ctx context.Context, path string, value interface{}) error {
+	return fc.Config.AppendConfigPathContext(ctx, path, value)
+}
+
+// ValidateConfig 校验完整的 JSON 配置而不应用 - 便利方法
+// 适合在 CI 中检查生成的配置；错误中包含出错的行列或字段
+// 只检查语法和主要字段的类型，未知模块等错误要到加载时才会发现
+func (fc *FastCaddy) ValidateConfig(cfg []byte) error {
+	return fc.API.ValidateConfig(cfg)
+}
+
+// ValidateConfigContext 校验完整的 JSON 配置而不应用（支持 context 取消和超时）
+func (fc *FastCaddy) ValidateConfigContext(ctx context.Context, cfg []byte) error {
+	return fc.API.ValidateConfigContext(ctx, cfg)
+}
+
+// PlannedChanges 返回预演模式下记录的变更 - 便利方法
+// 仅在使用 WithDryRun 创建客户端时有内容
+func (fc *FastCaddy) PlannedChanges() []PlannedChange {
+	return fc.API.PlannedChanges()
+}
+
+// LoadConfigWithRollback 加载完整配置，失败时自动回滚 - 便利方法
+func (fc *FastCaddy) LoadConfigWithRollback(newConfig []byte) error {
+	return fc.Config.LoadConfigWithRollback(newConfig)
+}
+
+// LoadConfigWithRollbackContext 加载完整配置，失败时自动回滚（支持 context 取消和超时）
+func (fc *FastCaddy) LoadConfigWithRollbackContext(ctx context.Context, newConfig []byte) error {
+	return fc.Config.LoadConfigWithRollbackContext(ctx, newConfig)
+}
+
+// Snapshot 获取当前运行的完整配置 - 便利方法
+// 用于灾难恢复，可原样传给 Restore
+func (fc *FastCaddy) Snapshot() ([]byte, error) {
+	return fc.Config.Snapshot()
+}
+
+// SnapshotContext 获取当前运行的完整配置（支持 context 取消和超时）
+func (fc *FastCaddy) SnapshotContext(ctx context.Context) ([]byte, error) {
+	return fc.Config.SnapshotContext(ctx)
+}
+
+// Restore 恢复 Snapshot 保存的配置 - 便利方法
+// 返回 true 表示配置发生了变化，快照与当前配置相同时不会重新加载
+func (fc *FastCaddy) Restore(snapshot []byte) (bool, error) {
+	return fc.Config.Restore(snapshot)
+}
+
+// RestoreContext 恢复配置快照（支持 context 取消和超时）
+func (fc *FastCaddy) RestoreContext(ctx context.Context, snapshot []byte) (bool, error) {
+	return fc.Config.RestoreContext(ctx, snapshot)
+}
+
+// Diff 比较期望的配置与当前运行的配置 - 便利方法
+// 返回规范化 JSON 之间的统一差异格式文本，配置相同时返回空字符串；ignore 中的配置路径不参与比较
+func (fc *FastCaddy) Diff(desired []byte, ignore ...string) (string, error) {
+	return fc.Config.Diff(desired, ignore...)
+}
+
+// DiffContext 比较期望的配置与当前运行的配置（支持 context 取消和超时）
+func (fc *FastCaddy) DiffContext(ctx context.Context, desired []byte, ignore ...string) (string, error) {
+	return fc.Config.DiffContext(ctx, desired, ignore...)
+}
@@ -2,28 +2,103 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 )
 
 // Client Caddy API 客户端 - 封装与 Caddy REST API 的交互
+// 可以在多个 goroutine 中并发使用
 type Client struct {
 	BaseURL    string       // Caddy API 基础 URL (默认: http://localhost:2019)
 	HTTPClient *http.Client // HTTP 客户端
+
+	dial    func(ctx context.Context, network, addr string) (net.Conn, error) // 在 Caddy 所在主机上建立连接
+	listen  func(network, addr string) (net.Listener, error)                  // 在 Caddy 所在主机上监听
+	dryRun  *dryRunState                                                      // 预演模式状态，为空时正常发送请求
+	lock    configLock                                                        // 串行化读取-修改-写入操作的配置锁
+	timeout time.Duration                                                     // 默认的单次请求超时，0 表示不设置
 }
 
 // NewClient 创建新的 Caddy API 客户端
+// 客户端内部复用连接池，应在多次调用之间共享同一个实例
 func NewClient() *Client {
-	return &Client{
-		BaseURL: "http://localhost:2019",
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	// 默认选项不会产生错误
+	client, _ := NewClientWithOptions(Options{})
+	return client
+}
+
+// NewClientWithOptions 根据选项创建新的 Caddy API 客户端
+// 选项之间无法组合时返回错误
+func NewClientWithOptions(opts Options) (*Client, error) {
+	baseURL := DefaultBaseURL
+	switch {
+	case opts.BaseURL != "" && opts.SocketPath != "":
+		return nil, fmt.Errorf("管理 API 地址和 Unix 套接字不能同时设置")
+	case opts.BaseURL != "":
+		normalized, err := ParseBaseURL(opts.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = normalized
+	case opts.SocketPath != "":
+		baseURL = unixBaseURL
+	}
+
+	if opts.SSHDialer != nil {
+		opts.sshConnector = newSSHConnector(opts.SSHDialer)
+	}
+
+	httpClient, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		BaseURL:    baseURL,
+		HTTPClient: httpClient,
+		dial:       newDialer(opts),
+		listen:     newListener(opts, baseURL),
+		timeout:    opts.Timeout,
+	}
+	if opts.DryRun {
+		client.dryRun = &dryRunState{}
+	}
+	return client, nil
+}
+
+// DialContext 在 Caddy 所在主机的网络视角下建立连接
+// 配置了 SSH 时通过 SSH 连接在远端拨号，用于检查 Caddy 对外提供的服务
+func (c *Client) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	dial := c.dial
+	if dial == nil {
+		dial = newDialer(Options{})
+	}
+	return dial(ctx, network, addr)
+}
+
+// Listen 在 Caddy 所在主机上监听，用于接收 Caddy 主动发起的连接 (如 net 日志输出)
+// 配置了 SSH 时通过 SSH 远程端口转发在远端监听；Caddy 不在本机且未配置 SSH 时返回错误
+func (c *Client) Listen(network, addr string) (net.Listener, error) {
+	listen := c.listen
+	if listen == nil {
+		listen = net.Listen
+	}
+	return listen(network, addr)
+}
+
+// Ping 检查 Caddy 管理 API 是否可达
+// 网络失败时返回连接错误，状态码异常时返回 APIError
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.getRaw(ctx, c.GetConfigURL("/apps")); err != nil {
+		return fmt.Errorf("Caddy 管理 API 不可用: %w", err)
 	}
+	return nil
 }
 
 // GetIDURL 根据路径生成 ID 的完整 URL - 用于通过 ID 访问配置
@@ -54,129 +129,257 @@ func (c *Client) GetConfigURL(path string) string {
 
 // GetByID 通过 ID 获取配置 - 对应 Python 的 gid(path) 函数
 func (c *Client) GetByID(path string) (map[string]interface{}, error) {
-	url := c.GetIDURL(path)
-	resp, err := c.HTTPClient.Get(url)
+	return c.GetByIDContext(context.Background(), path)
+}
+
+// GetByIDContext 通过 ID 获取配置（支持 context 取消和超时）
+func (c *Client) GetByIDContext(ctx context.Context, path string) (map[string]interface{}, error) {
+	data, err := c.getRaw(ctx, c.GetIDURL(path))
 	if err != nil {
 		return nil, fmt.Errorf("获取 ID 配置失败: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取 ID 配置失败, 状态码: %d", resp.StatusCode)
-	}
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("解析响应 JSON 失败: %w", err)
 	}
 
 	return result, nil
 }
 
+// GetRawByID 通过 ID 获取原始 JSON 配置
+func (c *Client) GetRawByID(path string) ([]byte, error) {
+	return c.GetRawByIDContext(context.Background(), path)
+}
+
+// GetRawByIDContext 通过 ID 获取原始 JSON 配置（支持 context 取消和超时）
+func (c *Client) GetRawByIDContext(ctx context.Context, path string) ([]byte, error) {
+	data, err := c.getRaw(ctx, c.GetIDURL(path))
+	if err != nil {
+		return nil, fmt.Errorf("获取 ID 配置失败: %w", err)
+	}
+	return data, nil
+}
+
 // GetConfig 获取指定路径的配置 - 对应 Python 的 gcfg(path, method) 函数
 func (c *Client) GetConfig(path string) (map[string]interface{}, error) {
-	url := c.GetConfigURL(path)
-	resp, err := c.HTTPClient.Get(url)
+	return c.GetConfigContext(context.Background(), path)
+}
+
+// GetConfigContext 获取指定路径的配置（支持 context 取消和超时）
+func (c *Client) GetConfigContext(ctx context.Context, path string) (map[string]interface{}, error) {
+	data, err := c.getRaw(ctx, c.GetConfigURL(path))
 	if err != nil {
 		return nil, fmt.Errorf("获取配置失败: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取配置失败, 状态码: %d", resp.StatusCode)
-	}
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("解析响应 JSON 失败: %w", err)
 	}
 
 	return result, nil
 }
 
+// GetRawConfig 获取指定路径的原始 JSON 配置
+func (c *Client) GetRawConfig(path string) ([]byte, error) {
+	return c.GetRawConfigContext(context.Background(), path)
+}
+
+// GetRawConfigContext 获取指定路径的原始 JSON 配置（支持 context 取消和超时）
+func (c *Client) GetRawConfigContext(ctx context.Context, path string) ([]byte, error) {
+	data, err := c.getRaw(ctx, c.GetConfigURL(path))
+	if err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+	return data, nil
+}
+
+// GetEndpoint 获取任意管理 API 端点的原始响应 (如 "/reverse_proxy/upstreams")
+func (c *Client) GetEndpoint(ctx context.Context, path string) ([]byte, error) {
+	data, err := c.getRaw(ctx, c.BaseURL+path)
+	if err != nil {
+		return nil, fmt.Errorf("请求 %s 失败: %w", path, err)
+	}
+	return data, nil
+}
+
 // HasID 检查指定 ID 是否已设置 - 对应 Python 的 has_id(id) 函数
 func (c *Client) HasID(id string) bool {
-	_, err := c.GetByID(id)
+	return c.HasIDContext(context.Background(), id)
+}
+
+// HasIDContext 检查指定 ID 是否已设置（支持 context 取消和超时）
+func (c *Client) HasIDContext(ctx context.Context, id string) bool {
+	_, err := c.GetByIDContext(ctx, id)
 	return err == nil
 }
 
 // HasPath 检查指定路径是否已设置 - 对应 Python 的 has_path(path) 函数
 func (c *Client) HasPath(path string) bool {
-	_, err := c.GetConfig(path)
+	return c.HasPathContext(context.Background(), path)
+}
+
+// HasPathContext 检查指定路径是否已设置（支持 context 取消和超时）
+func (c *Client) HasPathContext(ctx context.Context, path string) bool {
+	_, err := c.GetConfigContext(ctx, path)
 	return err == nil
 }
 
 // PutByID 将配置数据放入指定 ID 路径 - 对应 Python 的 pid(d, path, method) 函数
 func (c *Client) PutByID(data interface{}, path, method string) error {
+	return c.PutByIDContext(context.Background(), data, path, method)
+}
+
+// PutByIDContext 将配置数据放入指定 ID 路径（支持 context 取消和超时）
+func (c *Client) PutByIDContext(ctx context.Context, data interface{}, path, method string) error {
 	url := c.GetIDURL(path)
-	return c.sendRequest(method, url, data)
+	return c.sendRequest(ctx, method, url, data)
 }
 
 // PutConfig 将配置数据放入指定配置路径 - 对应 Python 的 pcfg(d, path, method) 函数
 func (c *Client) PutConfig(data interface{}, path, method string) error {
+	return c.PutConfigContext(context.Background(), data, path, method)
+}
+
+// PutConfigContext 将配置数据放入指定配置路径（支持 context 取消和超时）
+func (c *Client) PutConfigContext(ctx context.Context, data interface{}, path, method string) error {
 	url := c.GetConfigURL(path)
-	return c.sendRequest(method, url, data)
+	return c.sendRequest(ctx, method, url, data)
 }
 
 // DeleteByID 删除指定 ID 的配置 - 对应 Python 的 del_id(id) 函数
 func (c *Client) DeleteByID(id string) error {
-	url := c.GetIDURL(id)
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("创建删除请求失败: %w", err)
-	}
+	return c.DeleteByIDContext(context.Background(), id)
+}
 
-	resp, err := c.HTTPClient.Do(req)
+// DeleteByIDContext 删除指定 ID 的配置（支持 context 取消和超时）
+// ID 不存在时返回的错误满足 errors.Is(err, ErrNotFound)
+func (c *Client) DeleteByIDContext(ctx context.Context, id string) error {
+	url := c.GetIDURL(id)
+	resp, err := c.doRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return fmt.Errorf("发送删除请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("删除配置失败, 状态码: %d", resp.StatusCode)
+		return fmt.Errorf("删除配置失败: %w", newAPIError(resp))
 	}
 
 	return nil
 }
 
+// Load 通过 /load 端点整体替换 Caddy 配置
+// 配置按原样发送；Caddy 拒绝时返回 LoadError，其中包含可以确定的出错行列，原始 APIError 可通过 errors.As 获取
+func (c *Client) Load(config []byte) error {
+	return c.LoadContext(context.Background(), config)
+}
+
+// LoadContext 通过 /load 端点整体替换 Caddy 配置（支持 context 取消和超时）
+func (c *Client) LoadContext(ctx context.Context, config []byte) error {
+	err := c.sendRequest(ctx, http.MethodPost, c.BaseURL+"/load", json.RawMessage(config))
+	if apiErr, ok := err.(*APIError); ok {
+		return newLoadError(config, apiErr)
+	}
+	return err
+}
+
 // sendRequest 发送 HTTP 请求的通用方法 - 内部辅助函数
-func (c *Client) sendRequest(method, url string, data interface{}) error {
+func (c *Client) sendRequest(ctx context.Context, method, url string, data interface{}) error {
+	resp, err := c.doRequest(ctx, method, url, data)
+	if err != nil {
+		return fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 检查响应状态码
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// getRaw 发送 GET 请求并返回完整的响应体 - 内部辅助函数
+// 非 200 响应返回 APIError
+func (c *Client) getRaw(ctx context.Context, url string) ([]byte, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", wrapContextError(ctx, err))
+	}
+	return data, nil
+}
+
+// doRequest 构建并发送带 context 的 HTTP 请求 - 所有 API 调用的底层入口
+// json.RawMessage 类型的数据按原样发送，其他数据序列化为 JSON；调用方负责关闭返回的响应体
+// 预演模式下修改配置的请求不会发送，而是经过校验后记录下来
+func (c *Client) doRequest(ctx context.Context, method, url string, data interface{}) (*http.Response, error) {
+	return c.doRequestWithHeader(ctx, method, url, data, nil)
+}
+
+// doRequestWithHeader 与 doRequest 相同，并附加额外的请求头 (如 If-Match)
+func (c *Client) doRequestWithHeader(ctx context.Context, method, url string, data interface{}, header http.Header) (*http.Response, error) {
 	var body io.Reader
-	if data != nil {
+	var payload []byte
+	if raw, ok := data.(json.RawMessage); ok {
+		payload = raw
+		body = bytes.NewReader(raw)
+	} else if data != nil {
 		jsonData, err := json.Marshal(data)
 		if err != nil {
-			return fmt.Errorf("序列化请求数据失败: %w", err)
+			return nil, fmt.Errorf("序列化请求数据失败: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
+		payload = jsonData
+		body = bytes.NewReader(jsonData)
 	}
 
-	req, err := http.NewRequest(strings.ToUpper(method), url, body)
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, body)
 	if err != nil {
-		return fmt.Errorf("创建 HTTP 请求失败: %w", err)
+		cancel()
+		return nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
 	}
 
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if c.dryRun != nil && isMutating(req) {
+		defer cancel()
+		return c.dryRunResponse(ctx, req, payload)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("发送 HTTP 请求失败: %w", err)
+		cancel()
+		return nil, wrapContextError(ctx, err)
 	}
-	defer resp.Body.Close()
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	decodeResponse(resp)
 
-	// 检查响应状态码
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// 尝试读取错误信息
-		body, _ := io.ReadAll(resp.Body)
-		var errorMsg map[string]interface{}
-		if json.Unmarshal(body, &errorMsg) == nil {
-			if errStr, ok := errorMsg["error"].(string); ok {
-				return fmt.Errorf("请求失败, 状态码: %d, 错误: %s", resp.StatusCode, errStr)
-			}
-		}
-		return fmt.Errorf("请求失败, 状态码: %d", resp.StatusCode)
-	}
+	return resp, nil
+}
 
-	return nil
-}
\ No newline at end of file
+// wrapContextError 如果 context 已取消或超时，返回包装了 context 错误的结果
+// 这样调用方可以使用 errors.Is(err, context.DeadlineExceeded) 进行判断
+func wrapContextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %v", ctxErr, err)
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding 请求管理 API 时声明支持的压缩格式
+// 显式设置后 net/http 不再自动解压，因此由 decodeResponse 统一处理，
+// 这样自定义的 HTTP 客户端或 Transport (如关闭了 DisableCompression) 也能得到一致的结果
+const acceptEncoding = "gzip"
+
+// decodeResponse 按 Content-Encoding 透明解压响应体，调用方始终读取到原始 JSON
+func decodeResponse(resp *http.Response) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipBody 延迟创建 gzip 读取器，空响应体 (如 HEAD 请求) 不会因缺少 gzip 头而出错
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+// Read 实现 io.Reader 接口
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.err != nil {
+		return 0, g.err
+	}
+	if g.zr == nil {
+		g.zr, g.err = gzip.NewReader(g.body)
+		if g.err != nil {
+			return 0, g.err
+		}
+	}
+	return g.zr.Read(p)
+}
+
+// Close 实现 io.Closer 接口
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound ID 或配置路径不存在 - Caddy 返回 404 时的 APIError 满足 errors.Is(err, ErrNotFound)
+var ErrNotFound = errors.New("配置不存在")
+
+// APIError Caddy API 错误 - Caddy 返回非 2xx 状态码时的结构化错误
+// 可以通过 errors.As 获取，并根据 StatusCode 区分不同的失败原因
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Body       []byte // 原始响应体
+	Endpoint   string // 请求的 API 路径 (如 "/config/apps/http/")
+	Message    string // Caddy 返回的 {"error": "..."} 中的错误信息，可能为空
+	Module     string // 配置使用了 Caddy 未编译进来的模块时为模块 ID (如 "http.handlers.rate_limit")，否则为空
+}
+
+// Error 实现 error 接口
+func (e *APIError) Error() string {
+	if e.Module != "" {
+		return fmt.Sprintf("Caddy 未安装 %s 模块: Caddy API %s 请求失败, 状态码: %d, 错误: %s", e.Module, e.Endpoint, e.StatusCode, e.Message)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("Caddy API %s 请求失败, 状态码: %d, 错误: %s", e.Endpoint, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Caddy API %s 请求失败, 状态码: %d", e.Endpoint, e.StatusCode)
+}
+
+// Is 使 404 响应可以通过 errors.Is(err, ErrNotFound) 判断
+// 访问中间层级不存在的配置路径时 Caddy 返回 400 "invalid traversal path"，同样视为不存在
+func (e *APIError) Is(target error) bool {
+	if target != ErrNotFound {
+		return false
+	}
+	return e.StatusCode == http.StatusNotFound ||
+		(e.StatusCode == http.StatusBadRequest && strings.Contains(e.Message, "invalid traversal path"))
+}
+
+// ConflictError 条件更新失败 - 配置在读取之后已被其他客户端修改，If-Match 中的 Etag 不再匹配 (412)
+// 调用方应重新读取配置和 Etag 后重试
+type ConflictError struct {
+	Endpoint string    // 请求的 API 路径
+	Etag     string    // 请求中携带的 Etag
+	Err      *APIError // Caddy 返回的原始错误
+}
+
+// Error 实现 error 接口
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("配置 %s 已被修改, Etag %s 不再匹配", e.Endpoint, e.Etag)
+}
+
+// Unwrap 返回原始错误
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError 通过 /load 加载配置失败 - 解析 Caddy 返回的错误信息，并尽可能定位到提交配置中的出错位置
+// Caddy 的错误信息带有字节偏移量时直接使用；否则在本地重新解析提交的配置，JSON 语法错误时使用本地的偏移量
+type LoadError struct {
+	Message string    // Caddy 返回的错误信息
+	Offset  int64     // 出错位置在提交配置中的字节偏移量，未知时为 -1
+	Line    int       // 出错位置所在行（从 1 开始），未知时为 0
+	Column  int       // 出错位置所在列（从 1 开始），未知时为 0
+	Err     *APIError // Caddy 返回的原始错误
+}
+
+// Error 实现 error 接口
+func (e *LoadError) Error() string {
+	if e.Err != nil && e.Err.Module != "" {
+		return fmt.Sprintf("加载配置失败: Caddy 未安装 %s 模块: %s", e.Err.Module, e.Message)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("加载配置失败: 第 %d 行第 %d 列 (偏移量 %d): %s", e.Line, e.Column, e.Offset, e.Message)
+	}
+	return fmt.Sprintf("加载配置失败: %s", e.Message)
+}
+
+// Unwrap 返回原始错误
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// loadOffsetPattern 匹配 Caddy 错误信息中的字节偏移量 (如 "at offset 123")
+var loadOffsetPattern = regexp.MustCompile(`(?i)\boffset[ :=]*(\d+)`)
+
+// newLoadError 根据 /load 的错误响应创建 LoadError，config 为提交的配置
+func newLoadError(config []byte, apiErr *APIError) *LoadError {
+	loadErr := &LoadError{
+		Message: apiErr.Message,
+		Offset:  -1,
+		Err:     apiErr,
+	}
+	if loadErr.Message == "" {
+		loadErr.Message = strings.TrimSpace(string(apiErr.Body))
+	}
+
+	if m := loadOffsetPattern.FindStringSubmatch(loadErr.Message); m != nil {
+		if offset, err := strconv.ParseInt(m[1], 10, 64); err == nil && offset <= int64(len(config)) {
+			loadErr.Offset = offset
+		}
+	}
+	if loadErr.Offset < 0 {
+		// Caddy 使用 encoding/json 解析，本地得到的语法错误位置与其一致
+		var v interface{}
+		var syntaxErr *json.SyntaxError
+		if err := json.Unmarshal(config, &v); errors.As(err, &syntaxErr) {
+			loadErr.Offset = syntaxErr.Offset
+		}
+	}
+	if loadErr.Offset >= 0 {
+		loadErr.Line, loadErr.Column = OffsetToLineColumn(config, loadErr.Offset)
+	}
+
+	return loadErr
+}
+
+// IsNotFound 判断错误是否为 Caddy 返回的 404（ID 或配置路径不存在）
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// unknownModulePattern 匹配 Caddy 加载未编译进来的模块时的错误信息
+// (如 "unknown module: http.handlers.rate_limit" 或 "module not registered: dns.providers.cloudflare")
+var unknownModulePattern = regexp.MustCompile(`(?:unknown module|module not registered): ([\w.]+)`)
+
+// newAPIError 根据非 2xx 响应创建 APIError，并尝试解析 Caddy 的 JSON 错误信息
+// 所有添加、修改和加载配置的请求都经过这里，因此缺少模块的错误在任何方法中都能识别
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		apiErr.Endpoint = resp.Request.URL.Path
+	}
+
+	var errorMsg struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errorMsg) == nil {
+		apiErr.Message = errorMsg.Error
+	}
+	if m := unknownModulePattern.FindStringSubmatch(apiErr.Message); m != nil {
+		apiErr.Module = strings.TrimSuffix(m[1], ".")
+	}
+
+	return apiErr
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newErrorResponse 构造 Caddy 的错误响应
+func newErrorResponse(status int, body, path string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{URL: &url.URL{Path: path}},
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantMessage string
+		wantModule  string
+		wantFound   bool
+	}{
+		{"plain", 400, `{"error":"bad request"}`, "bad request", "", true},
+		{"not json", 500, `oops`, "", "", true},
+		{"not found", 404, `{"error":"unknown object ID 'x'"}`, "unknown object ID 'x'", "", false},
+		{"traversal", 400, `{"error":"invalid traversal path at: config/apps/tls"}`, "invalid traversal path at: config/apps/tls", "", false},
+		{"unknown handler", 400,
+			`{"error":"loading new config: loading http app module: provision http: server srv0: setting up route handlers: route 0: loading handler modules: position 0: loading module 'rate_limit': unknown module: http.handlers.rate_limit"}`,
+			"", "http.handlers.rate_limit", true},
+		{"not registered", 400, `{"error":"module not registered: dns.providers.cloudflare"}`,
+			"module not registered: dns.providers.cloudflare", "dns.providers.cloudflare", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(newErrorResponse(tt.status, tt.body, "/config/apps"))
+			if tt.wantMessage != "" && apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, 期望 %q", apiErr.Message, tt.wantMessage)
+			}
+			if apiErr.Module != tt.wantModule {
+				t.Errorf("Module = %q, 期望 %q", apiErr.Module, tt.wantModule)
+			}
+			if tt.wantModule != "" && !strings.Contains(apiErr.Error(), "Caddy 未安装 "+tt.wantModule+" 模块") {
+				t.Errorf("Error() = %q, 应说明缺少的模块", apiErr.Error())
+			}
+			if IsNotFound(apiErr) == tt.wantFound {
+				t.Errorf("IsNotFound = %v, 期望 %v", !tt.wantFound, tt.wantFound)
+			}
+			if apiErr.Endpoint != "/config/apps" || apiErr.StatusCode != tt.status {
+				t.Errorf("Endpoint/StatusCode = %q/%d", apiErr.Endpoint, apiErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestLoadErrorMissingModule(t *testing.T) {
+	apiErr := newAPIError(newErrorResponse(400, `{"error":"loading module 'rate_limit': unknown module: http.handlers.rate_limit"}`, "/load"))
+	err := error(newLoadError([]byte(`{}`), apiErr))
+	if !strings.Contains(err.Error(), "Caddy 未安装 http.handlers.rate_limit 模块") {
+		t.Errorf("Error() = %q, 应说明缺少的模块", err.Error())
+	}
+	var got *APIError
+	if !errors.As(err, &got) || got.Module != "http.handlers.rate_limit" {
+		t.Errorf("应能通过 errors.As 取得 Module")
+	}
+}
+
+func TestNewLoadError(t *testing.T) {
+	config := []byte("{\n  \"apps\": {\n    \"http\": 1\n  }\n}")
+	tests := []struct {
+		name       string
+		config     []byte
+		body       string
+		wantOffset int64
+		wantLine   int
+		wantColumn int
+	}{
+		{"offset in message", config,
+			`{"error":"loading config: json: cannot unmarshal number into Go value of type http.App at offset 27"}`, 27, 3, 13},
+		{"offset beyond config", config, `{"error":"bad value at offset 999"}`, -1, 0, 0},
+		{"local syntax error", []byte("{\n  \"apps\": ,\n}"), `{"error":"decoding request body: invalid character ','"}`, 13, 2, 11},
+		{"no position", config, `{"error":"loading new config: http app module: start: listening on :80: address already in use"}`, -1, 0, 0},
+		{"plain text body", config, "internal error\n", -1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(newErrorResponse(http.StatusBadRequest, tt.body, "/load"))
+			loadErr := newLoadError(tt.config, apiErr)
+			if loadErr.Offset != tt.wantOffset || loadErr.Line != tt.wantLine || loadErr.Column != tt.wantColumn {
+				t.Errorf("位置 = 偏移量 %d, %d:%d, 期望偏移量 %d, %d:%d",
+					loadErr.Offset, loadErr.Line, loadErr.Column, tt.wantOffset, tt.wantLine, tt.wantColumn)
+			}
+			if loadErr.Message == "" {
+				t.Error("Message 不应为空")
+			}
+			if !errors.Is(loadErr, apiErr) && loadErr.Err != apiErr {
+				t.Error("LoadError 应包装原始的 APIError")
+			}
+		})
+	}
+}
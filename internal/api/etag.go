@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetRawConfigWithEtag 获取指定路径的原始 JSON 配置及其 Etag
+// Etag 可传给 PutConfigIfMatch，实现"比较并交换"式的条件更新
+func (c *Client) GetRawConfigWithEtag(ctx context.Context, path string) ([]byte, string, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.GetConfigURL(path), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("获取配置失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("获取配置失败: %w", newAPIError(resp))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("读取响应失败: %w", wrapContextError(ctx, err))
+	}
+	return data, resp.Header.Get("Etag"), nil
+}
+
+// PutConfigIfMatch 仅当配置的 Etag 仍为 etag 时才写入指定配置路径
+// 配置已被修改时 Caddy 返回 412，此时返回 ConflictError
+func (c *Client) PutConfigIfMatch(ctx context.Context, data interface{}, path, method, etag string) error {
+	url := c.GetConfigURL(path)
+	resp, err := c.doRequestWithHeader(ctx, method, url, data, http.Header{"If-Match": {etag}})
+	if err != nil {
+		return fmt.Errorf("发送 HTTP 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		apiErr := newAPIError(resp)
+		return &ConflictError{Endpoint: apiErr.Endpoint, Etag: etag, Err: apiErr}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return newAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// lockKey 标记 context 已持有某个客户端的配置锁
+type lockKey struct{ client *Client }
+
+// configLock 客户端级别的配置锁 - 串行化"读取-修改-写入"类操作
+// 使用容量为 1 的通道实现，等待时可以响应 context 取消
+type configLock struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// Lock 获取客户端的配置锁，用于保护"读取配置、修改、写回"的操作不被同一客户端上的并发调用打断
+// 返回的 context 标记已持有锁，使用它发起的嵌套操作不会重复加锁（因此不会死锁）；
+// 操作结束后必须调用返回的 unlock。等待期间 context 被取消时返回错误。
+// 锁只在共享同一个 Client 的调用之间生效，无法防止其他进程对 Caddy 的并发修改
+func (c *Client) Lock(ctx context.Context) (context.Context, func(), error) {
+	if ctx.Value(lockKey{c}) != nil {
+		return ctx, func() {}, nil
+	}
+
+	c.lock.once.Do(func() { c.lock.ch = make(chan struct{}, 1) })
+	select {
+	case c.lock.ch <- struct{}{}:
+	case <-ctx.Done():
+		return ctx, nil, fmt.Errorf("等待配置锁失败: %w", ctx.Err())
+	}
+
+	var once sync.Once
+	unlock := func() { once.Do(func() { <-c.lock.ch }) }
+	return context.WithValue(ctx, lockKey{c}, true), unlock, nil
+}
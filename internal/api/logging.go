@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 常量定义 - 请求日志参数
+const (
+	maxLoggedBody = 64 << 10           // 每个请求体或响应体最多记录的字节数
+	redacted      = "[REDACTED]"       // 敏感值的替代文本
+	truncated     = "...(已截断)"         // 超出记录上限时追加的标记
+	privateKeyPEM = "PRIVATE KEY-----" // PEM 私钥块的标记，出现在任意字段中都会被隐藏
+	logMessageReq = "Caddy 管理 API 请求"  // 请求日志的消息
+	logMessageRes = "Caddy 管理 API 响应"  // 响应日志的消息
+)
+
+// sensitiveKeys 值会被隐藏的 JSON 字段名
+var sensitiveKeys = []string{"api_token", "auth_token", "mac_key", "password", "private_key", "secret", "token"}
+
+// loggingTransport 日志传输层 - 以 debug 级别记录每次发往管理 API 的请求和响应
+// 位于重试之内，每次尝试分别记录；请求体和响应体中的私钥、令牌等敏感值会被隐藏
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+// withLogging 按选项为传输层包装请求日志
+func withLogging(rt http.RoundTripper, opts Options) http.RoundTripper {
+	if opts.Logger != nil {
+		rt = &loggingTransport{next: rt, logger: opts.Logger}
+	}
+	return rt
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return t.next.RoundTrip(req)
+	}
+
+	body, req, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	over := len(body) > maxLoggedBody
+	if over {
+		body = body[:maxLoggedBody]
+	}
+	t.logger.DebugContext(ctx, logMessageReq,
+		"method", req.Method,
+		"url", req.URL.String(),
+		"body", redactBody(body, over))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.DebugContext(ctx, logMessageRes,
+			"method", req.Method,
+			"url", req.URL.String(),
+			"duration", time.Since(start),
+			"error", err)
+		return nil, err
+	}
+
+	// 响应体在调用方读取完毕或关闭时记录，不会改变流式端点的读取方式；
+	// 此时 decodeResponse 已删除 Content-Encoding，因此先记下压缩格式
+	gzipped := strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip")
+	status := resp.StatusCode
+	resp.Body = &loggingBody{
+		ReadCloser: resp.Body,
+		log: func(data []byte, over bool) {
+			if gzipped {
+				data = gunzipPrefix(data)
+			}
+			t.logger.DebugContext(ctx, logMessageRes,
+				"method", req.Method,
+				"url", req.URL.String(),
+				"status", status,
+				"duration", time.Since(start),
+				"body", redactBody(data, over))
+		},
+	}
+	return resp, nil
+}
+
+// readRequestBody 读取请求体用于记录，并返回请求体仍可发送的请求
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		return data, req, err
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	return data, clone, nil
+}
+
+// loggingBody 记录调用方读到的响应体前 maxLoggedBody 字节，在读到结尾或关闭时输出一次日志
+type loggingBody struct {
+	io.ReadCloser
+	log    func(data []byte, over bool)
+	buf    bytes.Buffer
+	over   bool
+	logged bool
+}
+
+// Read 实现 io.Reader 接口
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxLoggedBody - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+		b.over = b.over || n > room
+	} else if n > 0 {
+		b.over = true
+	}
+	if err == io.EOF {
+		b.flush()
+	}
+	return n, err
+}
+
+// Close 实现 io.Closer 接口
+func (b *loggingBody) Close() error {
+	b.flush()
+	return b.ReadCloser.Close()
+}
+
+// flush 输出响应日志，只执行一次
+func (b *loggingBody) flush() {
+	if b.logged {
+		return
+	}
+	b.logged = true
+	b.log(b.buf.Bytes(), b.over)
+}
+
+// gunzipPrefix 解压已记录的 gzip 响应体，截断的数据尽可能解压出前面的部分
+func gunzipPrefix(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return data
+	}
+	plain, _ := io.ReadAll(io.LimitReader(zr, maxLoggedBody))
+	return plain
+}
+
+// redactBody 隐藏请求体或响应体中的敏感值，返回用于日志的文本
+// JSON 中敏感字段的值以及包含 PEM 私钥的字符串会被替换；无法解析的内容含有私钥时整体隐藏
+func redactBody(data []byte, over bool) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	text := string(data)
+	if !over && json.Unmarshal(data, &v) == nil {
+		if out, err := json.Marshal(redactValue(v)); err == nil {
+			text = string(out)
+		}
+	} else if strings.Contains(text, privateKeyPEM) || containsSensitiveKey(text) {
+		// 截断或非 JSON 的内容无法逐字段处理
+		text = redacted
+	}
+
+	if over {
+		text += truncated
+	}
+	return text
+}
+
+// redactValue 递归替换 JSON 值中的敏感字段
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if isSensitiveKey(k) {
+				val[k] = redacted
+				continue
+			}
+			val[k] = redactValue(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+		return val
+	case string:
+		if strings.Contains(val, privateKeyPEM) {
+			return redacted
+		}
+		return val
+	default:
+		return val
+	}
+}
+
+// isSensitiveKey 判断 JSON 字段名是否属于敏感字段
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if key == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSensitiveKey 判断文本中是否出现了敏感字段名
+func containsSensitiveKey(text string) bool {
+	for _, s := range sensitiveKeys {
+		if strings.Contains(text, `"`+s+`"`) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// retryTransport 重试传输层 - 对幂等请求在网络错误和 5xx 响应时按指数退避重试
+// 非幂等请求（如向数组追加元素的 POST）只在请求发出前失败时重试；
+// 4xx 响应属于调用方错误，不会重试；context 取消或超时后立即停止
+type retryTransport struct {
+	next        http.RoundTripper
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !canReplay(req) {
+		return t.next.RoundTrip(req)
+	}
+	idempotent := isIdempotent(req)
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.maxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+		// 非幂等请求只有在确定没有发出时才能重试，否则 Caddy 可能已经应用了变更
+		if !idempotent && !requestNotSent(err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// 等待结束前就会超时，直接返回本次结果
+			return resp, err
+		}
+
+		// 丢弃本次响应，以便连接可以被复用
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff 计算第 attempt 次失败后的等待时间：指数增长并加入随机抖动
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay << (attempt - 1)
+	if delay <= 0 {
+		return 0
+	}
+	// 在 [delay/2, delay) 区间内随机，避免多个客户端同时重试
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// canReplay 判断请求体能否重新发送
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isIdempotent 判断请求在 Caddy 可能已经处理过的情况下能否安全重试
+// GET/HEAD 总是可以重试；/load 整体替换配置，重复加载结果相同；
+// 对配置路径的 PUT/PATCH/DELETE 重复执行时要么结果相同，要么因键已存在或不存在而失败。
+// 对配置的 POST 会向数组追加元素，对数组下标的 PUT 会插入元素、DELETE 会删除当前位于该下标的元素，
+// 重复执行会产生重复项或误删其他元素，因此不视为幂等
+func isIdempotent(req *http.Request) bool {
+	path := req.URL.Path
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return path == "/load"
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if !strings.HasPrefix(path, "/config/") && !strings.HasPrefix(path, "/id/") {
+			return false
+		}
+		return req.Method == http.MethodPatch || !isArrayIndex(path)
+	}
+	return false
+}
+
+// isArrayIndex 判断配置路径的最后一段是否为数组下标
+func isArrayIndex(path string) bool {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	_, err := strconv.Atoi(segments[len(segments)-1])
+	return err == nil
+}
+
+// requestNotSent 判断请求是否在发出之前就失败了：建立连接失败、连接被拒绝或 SSH 通道无法打开
+// 这类失败中 Caddy 没有收到请求，任何请求都可以安全重试
+func requestNotSent(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var channelErr *ssh.OpenChannelError
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &channelErr)
+}
+
+// shouldRetry 判断是否为可重试的瞬时失败：网络错误或 5xx 响应
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// sleepContext 等待指定时间，context 结束时提前返回其错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{http.MethodGet, "/config/apps/http/servers/srv0/routes", true},
+		{http.MethodHead, "/config/", true},
+		{http.MethodGet, "/reverse_proxy/upstreams", true},
+		{http.MethodPost, "/load", true},
+		{http.MethodPost, "/config/apps/http/servers/srv0/routes", false},
+		{http.MethodPost, "/config/apps/http/servers/srv0/routes/...", false},
+		{http.MethodPost, "/id/app_route/handle/0/upstreams", false},
+		{http.MethodPost, "/adapt", false},
+		{http.MethodPost, "/stop", false},
+		{http.MethodPut, "/config/apps/tls", true},
+		{http.MethodPut, "/id/app_route", true},
+		{http.MethodPut, "/config/apps/http/servers/srv0/routes/0", false},
+		{http.MethodPatch, "/config/apps/http/servers/srv0/routes/0", true},
+		{http.MethodPatch, "/id/app_route/match", true},
+		{http.MethodDelete, "/id/app_route", true},
+		{http.MethodDelete, "/config/apps/http/servers/srv0/routes/2", false},
+		{http.MethodPut, "/load", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "http://localhost:2019"+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := isIdempotent(req); got != tt.want {
+				t.Errorf("isIdempotent() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestNotSent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dial", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}, true},
+		{"refused", fmt.Errorf("wrapped: %w", syscall.ECONNREFUSED), true},
+		{"ssh channel", &ssh.OpenChannelError{Reason: ssh.ConnectionFailed, Message: "connect failed"}, true},
+		{"read", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, false},
+		{"eof", io.EOF, false},
+		{"unexpected eof", io.ErrUnexpectedEOF, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestNotSent(tt.err); got != tt.want {
+				t.Errorf("requestNotSent(%v) = %v, 期望 %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// stubTransport 按顺序返回预设结果并记录调用次数
+type stubTransport struct {
+	results []error // nil 表示返回 500 响应
+	calls   int
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(strings.NewReader(`{"error":"boom"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRetryTransport(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		results   []error
+		wantCalls int
+	}{
+		{"GET 5xx", http.MethodGet, "/config/", []error{nil}, 3},
+		{"GET EOF", http.MethodGet, "/config/", []error{io.EOF}, 3},
+		{"PUT 5xx", http.MethodPut, "/config/apps/tls", []error{nil}, 3},
+		{"load EOF", http.MethodPost, "/load", []error{io.EOF}, 3},
+		{"append 5xx", http.MethodPost, "/config/apps/http/servers/srv0/routes", []error{nil}, 1},
+		{"append EOF", http.MethodPost, "/config/apps/http/servers/srv0/routes", []error{io.EOF}, 1},
+		{"append refused", http.MethodPost, "/config/apps/http/servers/srv0/routes", []error{dialErr}, 3},
+		{"append refused then 5xx", http.MethodPost, "/config/apps/http/servers/srv0/routes", []error{dialErr, nil}, 2},
+		{"insert EOF", http.MethodPut, "/config/apps/http/servers/srv0/routes/0", []error{io.EOF}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTransport{results: tt.results}
+			rt := &retryTransport{next: stub, maxAttempts: 3, baseDelay: time.Millisecond}
+
+			req, err := http.NewRequestWithContext(context.Background(), tt.method,
+				"http://localhost:2019"+tt.path, strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if stub.calls != tt.wantCalls {
+				t.Errorf("请求次数 = %d, 期望 %d", stub.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportUnreplayableBody(t *testing.T) {
+	stub := &stubTransport{results: []error{io.EOF}}
+	rt := &retryTransport{next: stub, maxAttempts: 3, baseDelay: time.Millisecond}
+
+	req, err := http.NewRequest(http.MethodPut, "http://localhost:2019/config/apps/tls", io.NopCloser(strings.NewReader(`{}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, io.EOF) {
+		t.Fatalf("err = %v, 期望 EOF", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("无法重放的请求体被重试了 %d 次", stub.calls-1)
+	}
+}
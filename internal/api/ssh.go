@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshConnector SSH 连接管理器 - 按需建立 SSH 连接，并在连接断开后重新建立
+type sshConnector struct {
+	dial func() (*ssh.Client, error)
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// newSSHConnector 创建 SSH 连接管理器，首次使用时才会建立连接
+func newSSHConnector(dial func() (*ssh.Client, error)) *sshConnector {
+	return &sshConnector{dial: dial}
+}
+
+// get 返回当前可用的 SSH 连接，尚未连接或连接已断开时重新建立
+func (s *sshConnector) get() (*ssh.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	client, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("建立 SSH 连接失败: %w", err)
+	}
+	if client == nil {
+		return nil, fmt.Errorf("建立 SSH 连接失败: 拨号函数返回了空客户端")
+	}
+	s.client = client
+
+	// 连接关闭后清除，下次使用时自动重连
+	go func() {
+		client.Wait()
+		s.discard(client)
+	}()
+
+	return client, nil
+}
+
+// discard 丢弃已断开的 SSH 连接；当前连接已被替换时不做任何事
+func (s *sshConnector) discard(client *ssh.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == client {
+		s.client = nil
+		client.Close()
+	}
+}
+
+// alive 通过 keepalive 请求检查当前 SSH 连接是否仍然可用，不可用时将其丢弃
+func (s *sshConnector) alive() bool {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return false
+	}
+	if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+		s.discard(client)
+		return false
+	}
+	return true
+}
+
+// DialContext 通过 SSH 连接在远端建立连接
+// 打开通道失败时视为连接已断开，重新建立 SSH 连接后再尝试一次
+func (s *sshConnector) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err == nil || ctx.Err() != nil || s.alive() {
+		return conn, err
+	}
+
+	client, err = s.get()
+	if err != nil {
+		return nil, err
+	}
+	return client.DialContext(ctx, network, addr)
+}
+
+// Listen 通过 SSH 远程端口转发在远端主机上监听
+// SSH 连接已断开时重新建立后再尝试一次
+func (s *sshConnector) Listen(network, addr string) (net.Listener, error) {
+	client, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := client.Listen(network, addr)
+	if err == nil || s.alive() {
+		return listener, err
+	}
+
+	client, err = s.get()
+	if err != nil {
+		return nil, err
+	}
+	return client.Listen(network, addr)
+}
+
+// sshReconnectTransport SSH 重连传输层 - 请求因 SSH 连接断开而失败时，重连后重试一次
+type sshReconnectTransport struct {
+	next      http.RoundTripper
+	transport *http.Transport
+	connector *sshConnector
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *sshReconnectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil || req.Context().Err() != nil || t.connector.alive() {
+		return resp, err
+	}
+
+	// 无法重放的请求体不能重试
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, err
+	}
+
+	// 空闲连接都建立在已断开的 SSH 连接上，全部丢弃
+	t.transport.CloseIdleConnections()
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+		retryReq.Body = body
+	}
+	return t.next.RoundTrip(retryReq)
+}
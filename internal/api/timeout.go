@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"io"
+)
+
+// withDefaultTimeout 为没有截止时间的 context 加上客户端的默认请求超时
+// 调用方已设置截止时间时以调用方为准，可以比默认值更短或更长
+func (c *Client) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// cancelBody 在响应体关闭时释放请求的超时 context
+// 超时覆盖读取响应体的过程，因此不能在返回响应时就取消
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close 关闭响应体并释放超时 context
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
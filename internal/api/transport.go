@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 常量定义 - 默认连接参数
+const (
+	DefaultBaseURL = "http://localhost:2019" // Caddy 管理 API 默认地址
+	unixBaseURL    = "http://127.0.0.1"      // Unix 套接字模式下的占位主机，仅用于构造请求 URL
+	dialTimeout    = 30 * time.Second        // 建立连接的超时时间
+	requestTimeout = 30 * time.Second        // 默认 HTTP 客户端的整体请求超时
+
+	DefaultMaxIdleConns = 100              // 默认保留的空闲连接数
+	DefaultIdleTimeout  = 90 * time.Second // 默认空闲连接的保留时间
+)
+
+// Options 客户端选项 - 控制 API 客户端如何连接到 Caddy 管理端点
+type Options struct {
+	BaseURL    string                      // 管理 API 地址，为空时使用 DefaultBaseURL；不能与 SocketPath 同时设置
+	HTTPClient *http.Client                // 自定义 HTTP 客户端，为空时使用内置默认客户端
+	SocketPath string                      // Unix 套接字路径，非空时通过套接字连接管理 API
+	SSHClient  *ssh.Client                 // SSH 客户端，非空时所有连接都通过 SSH 在远端建立
+	SSHDialer  func() (*ssh.Client, error) // SSH 拨号函数，按需建立连接并在断开后自动重连，优先于 SSHClient
+
+	RetryAttempts  int           // 最大尝试次数（含首次请求），大于 1 时启用重试
+	RetryBaseDelay time.Duration // 首次重试前的基础等待时间，之后按指数增长
+
+	DryRun bool // 预演模式：修改配置的请求只校验和记录，不会发送给 Caddy
+
+	Timeout time.Duration // 默认的单次请求超时，覆盖从建立连接到读完响应体；context 已有截止时间时不生效
+
+	Logger *slog.Logger // 非空时以 debug 级别记录每次请求和响应，敏感值会被隐藏
+
+	MaxIdleConns int           // 保留的空闲连接数，0 表示使用默认值（自定义客户端则保持其原有设置）
+	IdleTimeout  time.Duration // 空闲连接的保留时间，0 表示使用默认值（自定义客户端则保持其原有设置）
+
+	sshConnector *sshConnector // 由 SSHDialer 创建，传输层和拨号函数共享同一个实例
+}
+
+// ParseBaseURL 校验并规范化管理 API 地址
+// 只接受 http/https 协议，必须包含主机，不能带路径、查询参数或片段
+func ParseBaseURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("无效的管理 API 地址 %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("无效的管理 API 地址 %q: 协议必须是 http 或 https", raw)
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("无效的管理 API 地址 %q: 缺少主机", raw)
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("无效的管理 API 地址 %q: 端口无效", raw)
+		}
+	}
+	if strings.TrimSuffix(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("无效的管理 API 地址 %q: 只能包含协议、主机和端口", raw)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// customDial 判断是否需要替换默认的 TCP 拨号
+func (o Options) customDial() bool {
+	return o.SSHClient != nil || o.sshConnector != nil || o.SocketPath != ""
+}
+
+// newHTTPClient 根据选项构建 HTTP 客户端
+// 提供了自定义客户端时，SSH/Unix 套接字拨号会安装到其 Transport 的副本上，
+// 调用方传入的客户端和 Transport 本身不会被修改
+func newHTTPClient(opts Options) (*http.Client, error) {
+	if opts.HTTPClient == nil {
+		if opts.MaxIdleConns == 0 {
+			opts.MaxIdleConns = DefaultMaxIdleConns
+		}
+		if opts.IdleTimeout == 0 {
+			opts.IdleTimeout = DefaultIdleTimeout
+		}
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		client := &http.Client{
+			Transport: newRoundTripper(base, opts),
+			Timeout:   requestTimeout,
+		}
+		// 设置了默认超时后由 context 控制截止时间，调用方才能指定比默认值更长的超时
+		if opts.Timeout > 0 {
+			client.Timeout = 0
+		}
+		return client, nil
+	}
+
+	client := *opts.HTTPClient
+	switch t := client.Transport.(type) {
+	case nil:
+		client.Transport = newRoundTripper(http.DefaultTransport.(*http.Transport).Clone(), opts)
+	case *http.Transport:
+		client.Transport = newRoundTripper(t.Clone(), opts)
+	default:
+		// 无法在任意 RoundTripper 上安装自定义拨号
+		if opts.customDial() {
+			return nil, fmt.Errorf("自定义 HTTP 客户端的 Transport 类型为 %T，必须是 *http.Transport 才能与 SSH 或 Unix 套接字组合使用", t)
+		}
+		client.Transport = withRetry(withLogging(t, opts), opts)
+	}
+
+	return &client, nil
+}
+
+// newRoundTripper 根据选项组装完整的传输链：底层连接 + 可选的 SSH 重连 + 可选的日志 + 可选的重试
+func newRoundTripper(base *http.Transport, opts Options) http.RoundTripper {
+	transport := newTransport(base, opts)
+
+	var rt http.RoundTripper = transport
+	if opts.sshConnector != nil {
+		rt = &sshReconnectTransport{
+			next:      rt,
+			transport: transport,
+			connector: opts.sshConnector,
+		}
+	}
+	return withRetry(withLogging(rt, opts), opts)
+}
+
+// withRetry 按选项为传输层包装重试逻辑
+func withRetry(rt http.RoundTripper, opts Options) http.RoundTripper {
+	if opts.RetryAttempts > 1 {
+		rt = &retryTransport{
+			next:        rt,
+			maxAttempts: opts.RetryAttempts,
+			baseDelay:   opts.RetryBaseDelay,
+		}
+	}
+	return rt
+}
+
+// newDialer 返回在管理端点所在主机上建立连接的拨号函数
+// 配置了 SSH 时连接从远端主机发起，否则从本机发起
+func newDialer(opts Options) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if opts.sshConnector != nil {
+		return opts.sshConnector.DialContext
+	}
+	if opts.SSHClient != nil {
+		return opts.SSHClient.DialContext
+	}
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	return dialer.DialContext
+}
+
+// newListener 返回在管理端点所在主机上监听的函数
+// 配置了 SSH 时在远端主机监听，管理端点为 Unix 套接字或本机地址时在本机监听
+func newListener(opts Options, baseURL string) func(network, addr string) (net.Listener, error) {
+	if opts.sshConnector != nil {
+		return opts.sshConnector.Listen
+	}
+	if opts.SSHClient != nil {
+		return opts.SSHClient.Listen
+	}
+	if opts.SocketPath != "" || isLoopbackURL(baseURL) {
+		return net.Listen
+	}
+	return func(network, addr string) (net.Listener, error) {
+		return nil, fmt.Errorf("Caddy 不在本机运行 (%s)，需要配置 SSH 才能在其所在主机上监听", baseURL)
+	}
+}
+
+// isLoopbackURL 判断地址的主机是否为本机 (localhost 或回环地址)
+func isLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newTransport 根据选项构建 HTTP 传输层
+// 同时设置了 SSH 和 Unix 套接字时，套接字在远端通过 SSH 连接拨号
+// 所有请求都发往同一个管理端点，因此每主机空闲连接数与总数相同，
+// 使高频更新时连接（SSH 模式下即 SSH 通道）被复用而不是每次重新建立
+func newTransport(transport *http.Transport, opts Options) *http.Transport {
+	if opts.MaxIdleConns > 0 {
+		transport.MaxIdleConns = opts.MaxIdleConns
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConns
+	}
+	if opts.IdleTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleTimeout
+	}
+
+	if !opts.customDial() {
+		return transport
+	}
+
+	// 管理端点不应经过环境变量中配置的 HTTP 代理
+	transport.Proxy = nil
+
+	dial, socketPath := newDialer(opts), opts.SocketPath
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if socketPath != "" {
+			network, addr = "unix", socketPath
+		}
+		return dial(ctx, network, addr)
+	}
+
+	return transport
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// AdaptPath Caddy 的配置适配端点，对 JSON 配置只检查语法，不会加载模块也不会应用
+const AdaptPath = "/adapt"
+
+// ValidationError 配置校验错误 - 指出问题所在的行列或字段
+type ValidationError struct {
+	Line   int    // 出错位置所在行（从 1 开始），未知时为 0
+	Column int    // 出错位置所在列（从 1 开始），未知时为 0
+	Field  string // 类型不匹配的字段路径 (如 "apps.http.servers.srv0.listen")，未知时为空
+	Err    error  // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Field != "" && e.Line > 0:
+		return fmt.Sprintf("配置校验失败: 第 %d 行第 %d 列, 字段 %s: %v", e.Line, e.Column, e.Field, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("配置校验失败: 第 %d 行第 %d 列: %v", e.Line, e.Column, e.Err)
+	default:
+		return fmt.Sprintf("配置校验失败: %v", e.Err)
+	}
+}
+
+// Unwrap 返回原始错误
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// PlannedChange 预演模式下记录的变更 - 描述本应发送给 Caddy 的修改请求
+type PlannedChange struct {
+	Method   string          // HTTP 方法
+	Endpoint string          // API 路径 (如 "/config/apps/http/servers/srv0/routes/")
+	Body     json.RawMessage // 请求体，DELETE 请求为空
+}
+
+// dryRunState 预演模式的状态 - 记录被拦截的变更请求
+type dryRunState struct {
+	mu      sync.Mutex
+	changes []PlannedChange
+}
+
+// ValidateConfig 校验完整的 Caddy JSON 配置，但不会应用
+// 本地检查 JSON 语法，以及 admin、logging、storage、apps.tls 是否为对象、
+// apps.http.servers.*.listen 是否为字符串数组、routes 是否为对象数组；
+// 随后发送到 Caddy 的 /adapt 端点，但该端点对 JSON 配置只检查语法，
+// 未知模块、处理器字段错误等问题只有在 /load 真正加载时才会报告
+func (c *Client) ValidateConfig(config []byte) error {
+	return c.ValidateConfigContext(context.Background(), config)
+}
+
+// ValidateConfigContext 校验完整的 Caddy JSON 配置（支持 context 取消和超时）
+func (c *Client) ValidateConfigContext(ctx context.Context, config []byte) error {
+	if err := checkConfigStructure(config); err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, c.BaseURL+AdaptPath, json.RawMessage(config))
+	if err != nil {
+		return fmt.Errorf("发送校验请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Caddy 拒绝了配置: %w", newAPIError(resp))
+	}
+	return nil
+}
+
+// PlannedChanges 返回预演模式下记录的所有变更，未启用预演模式时返回 nil
+func (c *Client) PlannedChanges() []PlannedChange {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+	return append([]PlannedChange(nil), c.dryRun.changes...)
+}
+
+// dryRunResponse 在预演模式下拦截变更请求：校验请求体、记录变更并返回模拟的成功响应
+func (c *Client) dryRunResponse(ctx context.Context, req *http.Request, payload []byte) (*http.Response, error) {
+	if len(payload) > 0 {
+		// 完整配置交给 Caddy 校验，其他局部修改只做本地检查
+		if req.URL.Path == "/load" {
+			if err := c.ValidateConfigContext(ctx, payload); err != nil {
+				return nil, err
+			}
+		} else if err := checkConfigSyntax(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	c.dryRun.mu.Lock()
+	c.dryRun.changes = append(c.dryRun.changes, PlannedChange{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Body:     json.RawMessage(payload),
+	})
+	c.dryRun.mu.Unlock()
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// isMutating 判断请求是否会修改 Caddy 的配置
+func isMutating(req *http.Request) bool {
+	if req.URL.Path == AdaptPath {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// checkConfigSyntax 检查 JSON 语法，出错时返回带行列信息的 ValidationError
+func checkConfigSyntax(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return newValidationError(data, err)
+	}
+	return nil
+}
+
+// configSchema 完整配置的主要结构，仅用于检查常见字段的类型
+type configSchema struct {
+	Admin   map[string]interface{} `json:"admin"`
+	Logging map[string]interface{} `json:"logging"`
+	Storage map[string]interface{} `json:"storage"`
+	Apps    struct {
+		HTTP struct {
+			Servers map[string]struct {
+				Listen []string                 `json:"listen"`
+				Routes []map[string]interface{} `json:"routes"`
+			} `json:"servers"`
+		} `json:"http"`
+		TLS map[string]interface{} `json:"tls"`
+	} `json:"apps"`
+}
+
+// checkConfigStructure 检查 JSON 语法及主要结构的字段类型
+func checkConfigStructure(data []byte) error {
+	if err := checkConfigSyntax(data); err != nil {
+		return err
+	}
+	var schema configSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return newValidationError(data, err)
+	}
+	return nil
+}
+
+// newValidationError 将 JSON 解析错误转换为带位置信息的 ValidationError
+func newValidationError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, column := OffsetToLineColumn(data, syntaxErr.Offset)
+		return &ValidationError{Line: line, Column: column, Err: err}
+	case errors.As(err, &typeErr):
+		line, column := OffsetToLineColumn(data, typeErr.Offset)
+		return &ValidationError{Line: line, Column: column, Field: typeErr.Field, Err: err}
+	default:
+		return &ValidationError{Err: err}
+	}
+}
+
+// OffsetToLineColumn 将 JSON 解析错误的偏移量转换为行号和列号（均从 1 开始）
+// encoding/json 报告的偏移量是出错时已读取的字节数，因此返回的是最后读取的字符所在位置
+func OffsetToLineColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	} else {
+		offset = 0
+	}
+	prefix := data[:offset]
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(prefix, '\n')
+	return line, column
+}
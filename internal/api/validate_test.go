@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckConfigStructure(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		wantLine   int
+		wantColumn int
+		wantField  string
+	}{
+		{"valid", `{"apps":{"http":{"servers":{"srv0":{"listen":[":443"],"routes":[]}}}}}`, 0, 0, ""},
+		{"unknown fields", `{"apps":{"layer4":{}},"extra":1}`, 0, 0, ""},
+		{"syntax", "{\n  \"apps\": {,\n}", 2, 12, ""},
+		{"listen type", "{\"apps\":{\"http\":{\"servers\":{\"srv0\":{\n\"listen\": \":443\"}}}}}", 2, 16, "apps.http.servers.srv0.listen"},
+		{"tls type", `{"apps":{"tls":[]}}`, 1, 16, "apps.tls"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkConfigStructure([]byte(tt.config))
+			if tt.wantLine == 0 {
+				if err != nil {
+					t.Fatalf("期望校验通过, 实际: %v", err)
+				}
+				return
+			}
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("期望 ValidationError, 实际: %v", err)
+			}
+			if vErr.Line != tt.wantLine || vErr.Column != tt.wantColumn || vErr.Field != tt.wantField {
+				t.Errorf("位置 = %d:%d %q, 期望 %d:%d %q",
+					vErr.Line, vErr.Column, vErr.Field, tt.wantLine, tt.wantColumn, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	var gotEncoding, gotType string
+	reject := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != AdaptPath || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		gotEncoding = r.Header.Get("Accept-Encoding")
+		gotType = r.Header.Get("Content-Type")
+		if reject {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"adapting config: bad"}`))
+			return
+		}
+		w.Write([]byte(`{"result":{}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ValidateConfig([]byte(`{"apps":{}}`)); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if gotEncoding != acceptEncoding || gotType != "application/json" {
+		t.Errorf("请求头 Accept-Encoding=%q Content-Type=%q, 期望经过共享的请求路径", gotEncoding, gotType)
+	}
+
+	var apiErr *APIError
+	reject = true
+	if err := c.ValidateConfig([]byte(`{}`)); !errors.As(err, &apiErr) {
+		t.Errorf("Caddy 拒绝时应返回 APIError, 实际: %v", err)
+	}
+}
+
+func TestValidateConfigDefaultTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithOptions(Options{BaseURL: srv.URL, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ValidateConfigContext(context.Background(), []byte(`{}`)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, 期望默认超时", err)
+	}
+}
+
+func TestOffsetToLineColumn(t *testing.T) {
+	data := []byte("{\n  \"a\": 1,\n  \"b\": x\n}")
+	tests := []struct {
+		offset     int64
+		wantLine   int
+		wantColumn int
+	}{
+		{0, 1, 1},
+		{1, 1, 1},  // 读取了 "{"
+		{2, 1, 2},  // 读取了第一行的换行符
+		{3, 2, 1},  // 第二行第一个字符
+		{20, 3, 8}, // 读取了 "x"
+		{-5, 1, 1},
+		{1000, 4, 1},
+	}
+	for _, tt := range tests {
+		line, column := OffsetToLineColumn(data, tt.offset)
+		if line != tt.wantLine || column != tt.wantColumn {
+			t.Errorf("OffsetToLineColumn(%d) = %d:%d, 期望 %d:%d", tt.offset, line, column, tt.wantLine, tt.wantColumn)
+		}
+	}
+}
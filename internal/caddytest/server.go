@@ -0,0 +1,327 @@
+// Package caddytest 提供模拟 Caddy 管理 API 的测试服务器
+// 按 Caddy 的配置路径语义实现 GET/POST/PUT/PATCH/DELETE、/id/<id> 和 /load，
+// 包括中间层级不存在时的 400 "invalid traversal path"，用于在没有 Caddy 的环境中测试各管理器
+package caddytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server 模拟的 Caddy 管理 API
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	config   interface{}
+	requests []string
+}
+
+// NewServer 以 JSON 格式的初始配置启动模拟服务器，测试结束时自动关闭
+// initial 为空时相当于尚未加载任何配置
+func NewServer(t testing.TB, initial string) *Server {
+	t.Helper()
+	s := &Server{}
+	if initial != "" {
+		if err := json.Unmarshal([]byte(initial), &s.config); err != nil {
+			t.Fatalf("解析初始配置失败: %v", err)
+		}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config 返回当前配置的 JSON，键按字母排序
+func (s *Server) Config() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, _ := json.Marshal(s.config)
+	return string(data)
+}
+
+// Get 返回配置路径上的值，路径不存在时返回 nil
+func (s *Server) Get(path string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.config
+	for _, key := range splitPath(path) {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			cur = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			cur = v[i]
+		default:
+			return nil
+		}
+	}
+	return cur
+}
+
+// Requests 返回收到的请求，格式为 "METHOD /path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// handle 处理管理 API 请求
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	body, _ := io.ReadAll(r.Body)
+	var val interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &val); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("decoding request body: %v", err))
+			return
+		}
+	}
+
+	path := r.URL.Path
+	switch {
+	case path == "/load":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.config = val
+		return
+	case strings.HasPrefix(path, "/id/"):
+		keys := splitPath(strings.TrimPrefix(path, "/id/"))
+		if len(keys) == 0 {
+			writeError(w, http.StatusBadRequest, "missing object ID")
+			return
+		}
+		prefix, ok := findID(s.config, keys[0], nil)
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown object ID '"+keys[0]+"'")
+			return
+		}
+		s.handleConfig(w, r.Method, append(prefix, keys[1:]...), val)
+	case strings.HasPrefix(path, "/config"):
+		s.handleConfig(w, r.Method, splitPath(strings.TrimPrefix(path, "/config")), val)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handleConfig 按 Caddy 的语义读取或修改配置路径
+func (s *Server) handleConfig(w http.ResponseWriter, method string, keys []string, val interface{}) {
+	// 路径以 ... 结尾时，POST 追加数组中的所有元素
+	ellipses := len(keys) > 0 && keys[len(keys)-1] == "..."
+	if ellipses {
+		keys = keys[:len(keys)-1]
+	}
+
+	if len(keys) == 0 {
+		switch method {
+		case http.MethodGet:
+			writeJSON(w, s.config)
+		case http.MethodDelete:
+			s.config = nil
+		default:
+			s.config = val
+		}
+		return
+	}
+
+	if s.config == nil {
+		if method == http.MethodGet {
+			writeJSON(w, nil)
+			return
+		}
+		s.config = map[string]interface{}{}
+	}
+
+	updated, result, errMsg := access(s.config, keys, "config", method, val, ellipses)
+	if errMsg != "" {
+		writeError(w, http.StatusBadRequest, errMsg)
+		return
+	}
+	if method == http.MethodGet {
+		writeJSON(w, result)
+		return
+	}
+	s.config = updated
+}
+
+// access 在 node 中沿 keys 定位并执行请求，返回修改后的节点、GET 的结果和错误信息
+// 中间层级不存在时与 Caddy 一样返回 "invalid traversal path"
+func access(node interface{}, keys []string, at, method string, val interface{}, ellipses bool) (interface{}, interface{}, string) {
+	key := keys[0]
+	at += "/" + key
+
+	if len(keys) > 1 {
+		next, ok := lookup(node, key)
+		if !ok || next == nil {
+			return nil, nil, "invalid traversal path at: " + at
+		}
+		updated, result, errMsg := access(next, keys[1:], at, method, val, ellipses)
+		if errMsg != "" || method == http.MethodGet {
+			return node, result, errMsg
+		}
+		return store(node, key, updated), nil, ""
+	}
+
+	switch v := node.(type) {
+	case map[string]interface{}:
+		existing, exists := v[key]
+		switch method {
+		case http.MethodGet:
+			return node, existing, ""
+		case http.MethodPost:
+			// 已有数组时追加，否则直接设置为请求的值
+			arr, isArray := existing.([]interface{})
+			switch {
+			case isArray && ellipses:
+				items, ok := val.([]interface{})
+				if !ok {
+					return nil, nil, "for ellipses, value must be an array"
+				}
+				v[key] = append(arr, items...)
+			case isArray:
+				v[key] = append(arr, val)
+			default:
+				v[key] = val
+			}
+		case http.MethodPut:
+			if exists {
+				return nil, nil, "[" + at + "] key already exists: " + key
+			}
+			v[key] = val
+		case http.MethodPatch:
+			if !exists {
+				return nil, nil, "[" + at + "] key does not exist: " + key
+			}
+			v[key] = val
+		case http.MethodDelete:
+			if !exists {
+				return nil, nil, "[" + at + "] key does not exist: " + key
+			}
+			delete(v, key)
+		}
+		return v, nil, ""
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, nil, "[" + at + "] invalid array index: " + key
+		}
+		inRange := i >= 0 && i < len(v)
+		switch method {
+		case http.MethodGet:
+			if !inRange {
+				return nil, nil, "[" + at + "] array index out of bounds: " + key
+			}
+			return node, v[i], ""
+		case http.MethodPut:
+			// 对数组元素使用 PUT 会在该位置插入
+			if i < 0 || i > len(v) {
+				return nil, nil, "[" + at + "] array index out of bounds: " + key
+			}
+			return append(v[:i:i], append([]interface{}{val}, v[i:]...)...), nil, ""
+		case http.MethodPatch:
+			if !inRange {
+				return nil, nil, "[" + at + "] array index out of bounds: " + key
+			}
+			v[i] = val
+			return v, nil, ""
+		case http.MethodDelete:
+			if !inRange {
+				return nil, nil, "[" + at + "] array index out of bounds: " + key
+			}
+			return append(v[:i:i], v[i+1:]...), nil, ""
+		default:
+			return nil, nil, "[" + at + "] method not allowed on array element"
+		}
+	default:
+		return nil, nil, "invalid traversal path at: " + at
+	}
+}
+
+// lookup 返回对象的键或数组的元素
+func lookup(node interface{}, key string) (interface{}, bool) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		next, ok := v[key]
+		return next, ok
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return v[i], true
+	}
+	return nil, false
+}
+
+// store 将修改后的子节点写回对象或数组
+func store(node interface{}, key string, value interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		v[key] = value
+	case []interface{}:
+		i, _ := strconv.Atoi(key)
+		v[i] = value
+	}
+	return node
+}
+
+// findID 查找 @id 等于 id 的对象，返回其配置路径
+func findID(node interface{}, id string, prefix []string) ([]string, bool) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if v["@id"] == id {
+			return prefix, true
+		}
+		for key, item := range v {
+			if p, ok := findID(item, id, append(append([]string(nil), prefix...), key)); ok {
+				return p, true
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if p, ok := findID(item, id, append(append([]string(nil), prefix...), strconv.Itoa(i))); ok {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// splitPath 将配置路径拆分为键，忽略首尾和重复的斜杠
+func splitPath(path string) []string {
+	var keys []string
+	for _, key := range strings.Split(path, "/") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 以 Caddy 的格式写入错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
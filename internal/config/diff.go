@@ -0,0 +1,290 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+)
+
+// diffContext 统一差异格式中每处变更前后保留的上下文行数
+const diffContext = 3
+
+// Diff 比较期望的配置与当前运行的配置，返回统一差异格式 (unified diff) 的文本
+// 两份配置都会规范化为按键排序、带缩进的 JSON，并忽略 null 值、空对象和空数组；
+// 除此之外的差异都会报告，包括 Caddy 运行时填充的字段和默认值，这类易变的路径可以通过 ignore 排除
+// (如 "/apps/tls/certificates/load_pem"，"*" 匹配任意键或数组下标)；配置相同时返回空字符串
+func (m *Manager) Diff(desired []byte, ignore ...string) (string, error) {
+	return m.DiffContext(context.Background(), desired, ignore...)
+}
+
+// DiffContext 比较期望的配置与当前运行的配置（支持 context 取消和超时）
+func (m *Manager) DiffContext(ctx context.Context, desired []byte, ignore ...string) (string, error) {
+	want, err := canonicalJSON(desired, ignore)
+	if err != nil {
+		return "", fmt.Errorf("期望的配置不是有效的 JSON: %w", err)
+	}
+
+	current, err := m.client.GetRawConfigContext(ctx, "/")
+	if err != nil {
+		return "", err
+	}
+	running, err := canonicalJSON(current, ignore)
+	if err != nil {
+		return "", fmt.Errorf("解析当前配置失败: %w", err)
+	}
+
+	return unifiedDiff("running", "desired", running, want), nil
+}
+
+// canonicalJSON 删除 ignore 中的配置路径后，将配置规范化为按键排序的缩进 JSON 行
+func canonicalJSON(data []byte, ignore []string) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	for _, path := range ignore {
+		value = removePath(value, utils.SplitPath(path))
+	}
+	value = prune(value)
+	if value == nil {
+		return nil, nil
+	}
+	// encoding/json 序列化 map 时按键排序
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(out), "\n"), nil
+}
+
+// removePath 删除配置中 keys 指向的值，"*" 匹配任意键或数组下标；路径不存在时不做修改
+// 数组元素被删除时置为 null，保持其他元素的位置不变
+func removePath(value interface{}, keys []string) interface{} {
+	if len(keys) == 0 {
+		return nil
+	}
+	key, rest := keys[0], keys[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if key != "*" && k != key {
+				continue
+			}
+			if updated := removePath(child, rest); updated == nil {
+				delete(v, k)
+			} else {
+				v[k] = updated
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if key != "*" && strconv.Itoa(i) != key {
+				continue
+			}
+			v[i] = removePath(child, rest)
+		}
+	}
+	return value
+}
+
+// prune 递归删除 null 值、空对象和空数组，它们与缺省字段在 Caddy 中含义相同
+func prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if pruned := prune(child); pruned == nil {
+				delete(v, key)
+			} else {
+				v[key] = pruned
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		// 数组元素的位置有意义，元素本身为空时保留为空对象
+		for i, child := range v {
+			if pruned := prune(child); pruned != nil {
+				v[i] = pruned
+			} else if _, ok := child.(map[string]interface{}); ok {
+				v[i] = map[string]interface{}{}
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// lineEdit 差异中的一行，op 为 ' '（相同）、'-'（删除）或 '+'（新增）
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// diffLines 使用 Myers 算法计算两组行之间的最短编辑序列
+func diffLines(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+
+	// trace[d] 保存第 d 步开始前 k ∈ [-d, d] 对角线上的最远位置，用于回溯
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	var edits []lineEdit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, lineEdit{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			edits = append(edits, lineEdit{'+', b[y-1]})
+			y--
+		} else {
+			edits = append(edits, lineEdit{'-', a[x-1]})
+			x--
+		}
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedDiff 生成统一差异格式的文本，没有差异时返回空字符串
+func unifiedDiff(fromName, toName string, a, b []string) string {
+	edits := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		// 找到下一处变更
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// 向后扩展，直到连续相同的行超过两倍上下文
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].op != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		from := start - diffContext
+		if from < 0 {
+			from = 0
+		}
+		to := end + diffContext
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		// 计算该区块在两份文本中的起始行号和行数
+		aLine, bLine := 1, 1
+		for _, e := range edits[:from] {
+			if e.op != '+' {
+				aLine++
+			}
+			if e.op != '-' {
+				bLine++
+			}
+		}
+		aCount, bCount := 0, 0
+		for _, e := range edits[from:to] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, e := range edits[from:to] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// hunkRange 格式化区块的行范围，行数为 0 时起始行号指向前一行
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/caddytest"
+)
+
+// newTestManager 创建连接到模拟服务器的配置管理器
+func newTestManager(t *testing.T, initial string) (*Manager, *caddytest.Server) {
+	t.Helper()
+	srv := caddytest.NewServer(t, initial)
+	client, err := api.NewClientWithOptions(api.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	return NewManagerWithClient(client), srv
+}
+
+// lines 将以空格分隔的单词拆分为行
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, " ")
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string // 每个编辑为 op+行，以空格分隔
+	}{
+		{"both empty", "", "", ""},
+		{"equal", "a b c", "a b c", " a  b  c"},
+		{"insert into empty", "", "a b", "+a +b"},
+		{"delete all", "a b", "", "-a -b"},
+		{"replace middle", "a b c", "a x c", " a -b +x  c"},
+		{"append", "a b", "a b c", " a  b +c"},
+		{"prepend", "b c", "a b c", "+a  b  c"},
+		{"classic", "a b c a b b a", "c b a b a c", "-a -b  c +b  a  b -b  a +c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := diffLines(lines(tt.a), lines(tt.b))
+			var got []string
+			aCount, bCount := 0, 0
+			for _, e := range edits {
+				got = append(got, string(e.op)+e.line)
+				if e.op != '+' {
+					aCount++
+				}
+				if e.op != '-' {
+					bCount++
+				}
+			}
+			if aCount != len(lines(tt.a)) || bCount != len(lines(tt.b)) {
+				t.Fatalf("编辑序列无法还原两组行: %v", got)
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("diffLines = %q, 期望 %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want string
+	}{
+		{"equal", lines("a b c"), lines("a b c"), ""},
+		{"single change", lines("a b c"), lines("a x c"),
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"from empty", nil, lines("a"),
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n"},
+		{"to empty", lines("a b"), nil,
+			"--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"context trimmed", lines("1 2 3 4 5 6 7 8"), lines("1 2 3 4 5 6 7 X"),
+			"--- old\n+++ new\n@@ -5,4 +5,4 @@\n 5\n 6\n 7\n-8\n+X\n"},
+		{"separate hunks", lines("a 1 2 3 4 5 6 7 8 b"), lines("A 1 2 3 4 5 6 7 8 B"),
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n"},
+		{"merged hunks", lines("a 1 2 3 4 5 6 b"), lines("A 1 2 3 4 5 6 B"),
+			"--- old\n+++ new\n@@ -1,8 +1,8 @@\n-a\n+A\n 1\n 2\n 3\n 4\n 5\n 6\n-b\n+B\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", tt.a, tt.b); got != tt.want {
+				t.Errorf("unifiedDiff =\n%s\n期望\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	running := `{"admin":{"listen":"localhost:2019"},"apps":{"http":{"servers":{
+		"srv0":{"listen":[":443"],"logs":{"default_logger_name":"log0"},"routes":[{"@id":"a","handle":[{"handler":"vars"}]}]},
+		"srv1":{"listen":[":80"],"logs":{"default_logger_name":"log1"},"errors":null}}}}}`
+
+	tests := []struct {
+		name    string
+		desired string
+		ignore  []string
+		want    []string // 差异中应出现的行，为空表示没有差异
+	}{
+		{"identical after prune", running, nil, nil},
+		{"null and empty ignored",
+			`{"admin":{"listen":"localhost:2019"},"storage":{},"apps":{"http":{"servers":{
+			"srv0":{"listen":[":443"],"logs":{"default_logger_name":"log0"},"routes":[{"@id":"a","handle":[{"handler":"vars"}]}],"tls_connection_policies":[]},
+			"srv1":{"listen":[":80"],"logs":{"default_logger_name":"log1"}}}}}}`, nil, nil},
+		{"volatile paths reported", `{"apps":{"http":{"servers":{
+			"srv0":{"listen":[":443"],"routes":[{"@id":"a","handle":[{"handler":"vars"}]}]},
+			"srv1":{"listen":[":80"]}}}}}`, nil, []string{`-  "admin": {`, `-          "logs": {`}},
+		{"ignored paths", `{"apps":{"http":{"servers":{
+			"srv0":{"listen":[":443"],"routes":[{"@id":"a","handle":[{"handler":"vars"}]}]},
+			"srv1":{"listen":[":80"]}}}}}`, []string{"/admin", "/apps/http/servers/*/logs"}, nil},
+		{"ignored array element", `{"admin":{"listen":"localhost:2019"},"apps":{"http":{"servers":{
+			"srv0":{"listen":[":443"],"logs":{"default_logger_name":"log0"},"routes":[{"@id":"b"}]},
+			"srv1":{"listen":[":80"],"logs":{"default_logger_name":"log1"}}}}}}`, []string{"/apps/http/servers/srv0/routes/0"}, nil},
+		{"real change", strings.Replace(running, `":80"`, `":8080"`, 1), []string{"/admin"},
+			[]string{`-            ":80"`, `+            ":8080"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t, running)
+			diff, err := m.Diff([]byte(tt.desired), tt.ignore...)
+			if err != nil {
+				t.Fatalf("Diff 失败: %v", err)
+			}
+			if len(tt.want) == 0 {
+				if diff != "" {
+					t.Errorf("期望没有差异, 实际:\n%s", diff)
+				}
+				return
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(diff, line+"\n") {
+					t.Errorf("差异中缺少 %q:\n%s", line, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffInvalidJSON(t *testing.T) {
+	m, srv := newTestManager(t, "")
+	if _, err := m.Diff([]byte(`{"apps":`)); err == nil {
+		t.Fatal("期望的配置无效时应返回错误")
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("期望的配置无效时不应请求 Caddy: %v", srv.Requests())
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RollbackTimeout 回滚操作的超时时间
+// 回滚不受调用方 context 取消的影响，以免新配置失败后无法恢复
+const RollbackTimeout = 30 * time.Second
+
+// LoadConfigWithRollback 加载完整配置，失败时恢复到之前的配置
+// 先保存当前运行的配置，再通过 /load 加载新配置；加载失败时重新加载保存的配置，
+// 返回的错误包装了原始错误，并说明回滚是否成功
+func (m *Manager) LoadConfigWithRollback(newConfig []byte) error {
+	return m.LoadConfigWithRollbackContext(context.Background(), newConfig)
+}
+
+// LoadConfigWithRollbackContext 加载完整配置，失败时恢复到之前的配置（支持 context 取消和超时）
+func (m *Manager) LoadConfigWithRollbackContext(ctx context.Context, newConfig []byte) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !json.Valid(newConfig) {
+		return fmt.Errorf("新配置不是有效的 JSON")
+	}
+
+	// 保存当前配置，作为回滚的基准
+	previous, err := m.client.GetRawConfigContext(ctx, "/")
+	if err != nil {
+		return fmt.Errorf("保存当前配置失败: %w", err)
+	}
+
+	loadErr := m.client.LoadContext(ctx, newConfig)
+	if loadErr == nil {
+		return nil
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RollbackTimeout)
+	defer cancel()
+
+	if err := m.client.LoadContext(rollbackCtx, previous); err != nil {
+		return fmt.Errorf("加载新配置失败: %w (回滚失败: %v)", loadErr, err)
+	}
+	return fmt.Errorf("加载新配置失败，已回滚到之前的配置: %w", loadErr)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"strings"
 
 	"github.com/OrbitDeploy/fastcaddy/internal/api"
@@ -13,8 +14,13 @@ type Manager struct {
 
 // NewManager 创建新的配置管理器
 func NewManager() *Manager {
+	return NewManagerWithClient(api.NewClient())
+}
+
+// NewManagerWithClient 使用指定的 API 客户端创建配置管理器
+func NewManagerWithClient(client *api.Client) *Manager {
 	return &Manager{
-		client: api.NewClient(),
+		client: client,
 	}
 }
 
@@ -74,8 +80,19 @@ func KeysToPath(keys ...string) string {
 // NestedSetConfig 在配置中设置嵌套值 - 对应 Python 的 nested_setcfg(value, *keys) 函数
 // 获取当前配置，更新嵌套值，然后保存回去
 func (m *Manager) NestedSetConfig(value interface{}, keys ...string) error {
+	return m.NestedSetConfigContext(context.Background(), value, keys...)
+}
+
+// NestedSetConfigContext 在配置中设置嵌套值（支持 context 取消和超时）
+func (m *Manager) NestedSetConfigContext(ctx context.Context, value interface{}, keys ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 获取当前配置
-	config, err := m.client.GetConfig("/")
+	config, err := m.client.GetConfigContext(ctx, "/")
 	if err != nil {
 		return err
 	}
@@ -84,12 +101,23 @@ func (m *Manager) NestedSetConfig(value interface{}, keys ...string) error {
 	updatedConfig := NestedSetDict(config, value, keys...)
 
 	// 保存更新后的配置
-	return m.client.PutConfig(updatedConfig, "/", "POST")
+	return m.client.PutConfigContext(ctx, updatedConfig, "/", "POST")
 }
 
 // InitPath 初始化配置路径 - 对应 Python 的 init_path(path, skip) 函数
 // 逐步创建路径中的每个层级，跳过指定数量的初始层级
 func (m *Manager) InitPath(path string, skip int) error {
+	return m.InitPathContext(context.Background(), path, skip)
+}
+
+// InitPathContext 初始化配置路径（支持 context 取消和超时）
+func (m *Manager) InitPathContext(ctx context.Context, path string, skip int) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	keys := PathToKeys(path)
 	var currentKeys []string
 
@@ -106,7 +134,7 @@ func (m *Manager) InitPath(path string, skip int) error {
 		currentPath := KeysToPath(currentKeys...)
 		emptyConfig := make(map[string]interface{})
 
-		if err := m.client.PutConfig(emptyConfig, currentPath, "POST"); err != nil {
+		if err := m.client.PutConfigContext(ctx, emptyConfig, currentPath, "POST"); err != nil {
 			return err
 		}
 	}
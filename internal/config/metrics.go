@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"fmt"
+)
+
+// 常量定义 - 指标配置路径和管理 API 的指标端点
+const (
+	MetricsPath     = "/apps/http/metrics" // HTTP 应用的指标配置，存在时收集每个服务器的请求指标
+	MetricsEndpoint = "/metrics"           // 管理 API 提供的 Prometheus 指标端点
+)
+
+// EnableMetrics 开启 HTTP 服务器的请求指标收集
+// 已开启时不做任何修改，保留现有的指标配置 (如 per_host)；需要已有 HTTP 应用配置
+func (m *Manager) EnableMetrics() error {
+	return m.EnableMetricsContext(context.Background())
+}
+
+// EnableMetricsContext 开启 HTTP 服务器的请求指标收集（支持 context 取消和超时）
+func (m *Manager) EnableMetricsContext(ctx context.Context) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	exists, err := m.pathExists(ctx, MetricsPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if err := m.client.PutConfigContext(ctx, map[string]interface{}{}, MetricsPath, "PUT"); err != nil {
+		return fmt.Errorf("开启指标收集失败: %w", err)
+	}
+	return nil
+}
+
+// GetMetrics 获取 Prometheus 文本格式的指标
+// 通过与其他请求相同的传输层访问管理 API 的 /metrics 端点，SSH 和 Unix 套接字连接同样适用
+func (m *Manager) GetMetrics(ctx context.Context) ([]byte, error) {
+	data, err := m.client.GetEndpoint(ctx, MetricsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("获取指标失败: %w", err)
+	}
+	return data, nil
+}
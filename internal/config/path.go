@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+)
+
+// GetConfigPath 获取任意配置路径的原始 JSON (如 "/apps/http/servers/srv0/routes")
+// 路径中不存在的最后一级键返回 null
+func (m *Manager) GetConfigPath(path string) ([]byte, error) {
+	return m.GetConfigPathContext(context.Background(), path)
+}
+
+// GetConfigPathContext 获取任意配置路径的原始 JSON（支持 context 取消和超时）
+func (m *Manager) GetConfigPathContext(ctx context.Context, path string) ([]byte, error) {
+	return m.client.GetRawConfigContext(ctx, path)
+}
+
+// GetConfigIndented 获取完整配置并格式化为带缩进的 JSON，便于人工查看
+func (m *Manager) GetConfigIndented() ([]byte, error) {
+	return m.GetConfigIndentedContext(context.Background())
+}
+
+// GetConfigIndentedContext 获取格式化后的完整配置（支持 context 取消和超时）
+func (m *Manager) GetConfigIndentedContext(ctx context.Context) ([]byte, error) {
+	data, err := m.client.GetRawConfigContext(ctx, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(data), "", "  "); err != nil {
+		return nil, fmt.Errorf("格式化配置失败: %w", err)
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// SetConfigPath 将指定配置路径的值整体替换为 value
+// 路径已有值时使用 PATCH 替换，否则使用 PUT 创建，只影响该子树，不会重写整个配置
+func (m *Manager) SetConfigPath(path string, value interface{}) error {
+	return m.SetConfigPathContext(context.Background(), path, value)
+}
+
+// SetConfigPathContext 将指定配置路径的值整体替换为 value（支持 context 取消和超时）
+func (m *Manager) SetConfigPathContext(ctx context.Context, path string, value interface{}) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	exists, err := m.pathExists(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	// Caddy 的 POST 对数组是追加而不是替换，因此替换已有值必须使用 PATCH
+	method := "PUT"
+	if exists {
+		method = "PATCH"
+	}
+	return m.client.PutConfigContext(ctx, value, path, method)
+}
+
+// GetConfigPathWithEtag 获取配置路径的原始 JSON 及其 Etag
+// 与 SetConfigPathIfMatch 配合实现乐观并发控制
+func (m *Manager) GetConfigPathWithEtag(path string) ([]byte, string, error) {
+	return m.GetConfigPathWithEtagContext(context.Background(), path)
+}
+
+// GetConfigPathWithEtagContext 获取配置路径的原始 JSON 及其 Etag（支持 context 取消和超时）
+func (m *Manager) GetConfigPathWithEtagContext(ctx context.Context, path string) ([]byte, string, error) {
+	return m.client.GetRawConfigWithEtag(ctx, path)
+}
+
+// SetConfigPathIfMatch 仅当配置未被修改 (Etag 仍为 etag) 时才替换指定配置路径的值
+// 配置已被其他客户端修改时返回 ConflictError，调用方应重新读取后重试，而不是覆盖对方的修改
+func (m *Manager) SetConfigPathIfMatch(path string, value interface{}, etag string) error {
+	return m.SetConfigPathIfMatchContext(context.Background(), path, value, etag)
+}
+
+// SetConfigPathIfMatchContext 仅当配置未被修改时才替换指定配置路径的值（支持 context 取消和超时）
+func (m *Manager) SetConfigPathIfMatchContext(ctx context.Context, path string, value interface{}, etag string) error {
+	if etag == "" {
+		return fmt.Errorf("Etag 不能为空")
+	}
+
+	exists, err := m.pathExists(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	method := "PUT"
+	if exists {
+		method = "PATCH"
+	}
+	return m.client.PutConfigIfMatch(ctx, value, path, method, etag)
+}
+
+// AppendConfigPath 向指定配置路径的数组追加一个元素
+func (m *Manager) AppendConfigPath(path string, value interface{}) error {
+	return m.AppendConfigPathContext(context.Background(), path, value)
+}
+
+// AppendConfigPathContext 向指定配置路径的数组追加一个元素（支持 context 取消和超时）
+func (m *Manager) AppendConfigPathContext(ctx context.Context, path string, value interface{}) error {
+	return m.client.PutConfigContext(ctx, value, path, "POST")
+}
+
+// pathExists 检查配置路径上是否已有值
+// Caddy 对父对象存在但键不存在的路径返回 null
+func (m *Manager) pathExists(ctx context.Context, path string) (bool, error) {
+	data, err := m.client.GetRawConfigContext(ctx, path)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("检查配置路径 %s 失败: %w", path, err)
+	}
+	return !bytes.Equal(bytes.TrimSpace(data), []byte("null")), nil
+}
+
+// EnsurePath 确保配置路径上的每一级对象都存在
+// 只为缺失的层级创建空对象，已有的配置不会被覆盖
+func (m *Manager) EnsurePath(path string) error {
+	return m.EnsurePathContext(context.Background(), path)
+}
+
+// EnsurePathContext 确保配置路径上的每一级对象都存在（支持 context 取消和超时）
+func (m *Manager) EnsurePathContext(ctx context.Context, path string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	keys := PathToKeys(path)
+	for i := range keys {
+		currentPath := KeysToPath(keys[:i+1]...)
+		exists, err := m.pathExists(ctx, currentPath)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := m.client.PutConfigContext(ctx, map[string]interface{}{}, currentPath, "PUT"); err != nil {
+			return fmt.Errorf("创建配置路径 %s 失败: %w", currentPath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Snapshot 获取当前运行的完整配置 (GET /config/)，可原样传给 Restore 恢复
+func (m *Manager) Snapshot() ([]byte, error) {
+	return m.SnapshotContext(context.Background())
+}
+
+// SnapshotContext 获取当前运行的完整配置（支持 context 取消和超时）
+func (m *Manager) SnapshotContext(ctx context.Context) ([]byte, error) {
+	data, err := m.client.GetRawConfigContext(ctx, "/")
+	if err != nil {
+		return nil, fmt.Errorf("获取配置快照失败: %w", err)
+	}
+	return data, nil
+}
+
+// Restore 通过 /load 恢复 Snapshot 保存的配置
+// 快照必须是非空的有效 JSON；与当前配置相同时不发送加载请求，返回值表示配置是否发生了变化
+func (m *Manager) Restore(snapshot []byte) (bool, error) {
+	return m.RestoreContext(context.Background(), snapshot)
+}
+
+// RestoreContext 通过 /load 恢复配置快照（支持 context 取消和超时）
+func (m *Manager) RestoreContext(ctx context.Context, snapshot []byte) (bool, error) {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	trimmed := bytes.TrimSpace(snapshot)
+	if len(trimmed) == 0 {
+		return false, fmt.Errorf("配置快照为空")
+	}
+
+	var desired interface{}
+	if err := json.Unmarshal(trimmed, &desired); err != nil {
+		return false, fmt.Errorf("配置快照不是有效的 JSON: %w", err)
+	}
+	if desired != nil {
+		if _, ok := desired.(map[string]interface{}); !ok {
+			return false, fmt.Errorf("配置快照必须是 JSON 对象")
+		}
+	}
+
+	current, err := m.SnapshotContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	var running interface{}
+	if err := json.Unmarshal(current, &running); err != nil {
+		return false, fmt.Errorf("解析当前配置失败: %w", err)
+	}
+	if reflect.DeepEqual(desired, running) {
+		return false, nil
+	}
+
+	// 空配置的快照为 null，恢复时以空对象加载
+	if desired == nil {
+		trimmed = []byte("{}")
+	}
+	if err := m.client.LoadContext(ctx, trimmed); err != nil {
+		return false, fmt.Errorf("恢复配置失败: %w", err)
+	}
+	return true, nil
+}
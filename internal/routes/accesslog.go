@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+const (
+	// LogsPath logging 应用中自定义日志的配置路径
+	LogsPath = "/logging/logs"
+	// DefaultLogName Caddy 默认日志的名称
+	DefaultLogName = "default"
+	// accessLoggerPrefix HTTP 访问日志记录器名称的前缀
+	accessLoggerPrefix = "http.log.access."
+)
+
+// 支持的日志编码格式
+var logFormats = []string{"json", "console"}
+
+// EnableAccessLog 将服务器的访问日志写入按大小滚动的文件
+// 在 logging/logs/<日志名称> 下创建只接收该服务器访问日志的文件日志，并设置服务器的 logs.default_logger_name；
+// 同时把这些条目从默认日志中排除，避免重复输出到标准错误
+func (m *Manager) EnableAccessLog(serverName string, opts types.AccessLogOptions) error {
+	return m.EnableAccessLogContext(context.Background(), serverName, opts)
+}
+
+// EnableAccessLogContext 将服务器的访问日志写入按大小滚动的文件（支持 context 取消和超时）
+func (m *Manager) EnableAccessLogContext(ctx context.Context, serverName string, opts types.AccessLogOptions) error {
+	log, err := AccessLog(opts)
+	if err != nil {
+		return err
+	}
+
+	loggerName := opts.LoggerName
+	if loggerName == "" {
+		loggerName = serverName
+	}
+	if strings.ContainsAny(loggerName, "./") {
+		return fmt.Errorf("日志名称不能包含 . 或 /: %s", loggerName)
+	}
+	if loggerName == DefaultLogName {
+		return fmt.Errorf("日志名称不能为 %s", DefaultLogName)
+	}
+	log.Include = []string{accessLoggerPrefix + loggerName}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := m.getServer(ctx, serverName); err != nil {
+		return err
+	}
+
+	if err := m.configManager.EnsurePathContext(ctx, LogsPath); err != nil {
+		return err
+	}
+	if err := m.configManager.SetConfigPathContext(ctx, LogsPath+"/"+loggerName, log); err != nil {
+		return fmt.Errorf("配置日志 %s 失败: %w", loggerName, err)
+	}
+	if err := m.excludeFromDefaultLog(ctx, accessLoggerPrefix+loggerName); err != nil {
+		return err
+	}
+
+	logsPath := ServerPath(serverName) + "/logs"
+	if err := m.configManager.EnsurePathContext(ctx, logsPath); err != nil {
+		return err
+	}
+	return m.configManager.SetConfigPathContext(ctx, logsPath+"/default_logger_name", loggerName)
+}
+
+// AccessLog 生成写入文件的自定义日志配置，不发送到 Caddy
+// Filename 必须是 Caddy 所在主机上的绝对路径，滚动参数不能为负数
+func AccessLog(opts types.AccessLogOptions) (types.CustomLog, error) {
+	// 远端的 Caddy 可能运行在不同的操作系统上，两种绝对路径形式都接受
+	if !path.IsAbs(opts.Filename) && !filepath.IsAbs(opts.Filename) {
+		return types.CustomLog{}, fmt.Errorf("日志文件必须是绝对路径: %s", opts.Filename)
+	}
+	if strings.HasSuffix(opts.Filename, "/") || strings.HasSuffix(opts.Filename, `\`) {
+		return types.CustomLog{}, fmt.Errorf("日志文件路径不能是目录: %s", opts.Filename)
+	}
+	if opts.RollSizeMB < 0 || opts.RollKeep < 0 || opts.RollKeepDays < 0 {
+		return types.CustomLog{}, fmt.Errorf("日志滚动参数不能为负数")
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = "json"
+	}
+	if !utils.StringSliceContains(logFormats, format) {
+		return types.CustomLog{}, fmt.Errorf("不支持的日志格式: %s", format)
+	}
+
+	return types.CustomLog{
+		Writer: &types.LogWriter{
+			Output:       "file",
+			Filename:     opts.Filename,
+			RollSizeMB:   opts.RollSizeMB,
+			RollKeep:     opts.RollKeep,
+			RollKeepDays: opts.RollKeepDays,
+		},
+		Encoder: &types.LogEncoder{Format: format},
+	}, nil
+}
+
+// excludeFromDefaultLog 将日志记录器加入默认日志的 exclude 列表，已存在时不做修改
+// 默认日志未配置时创建只包含 exclude 的默认日志，其输出与 Caddy 的内置默认值相同
+func (m *Manager) excludeFromDefaultLog(ctx context.Context, logger string) error {
+	excludePath := LogsPath + "/" + DefaultLogName + "/exclude"
+
+	var exclude []string
+	data, err := m.client.GetRawConfigContext(ctx, excludePath)
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("获取默认日志配置失败: %w", err)
+	}
+	if err == nil {
+		// 不存在的键 Caddy 返回 null
+		if err := json.Unmarshal(data, &exclude); err != nil {
+			return fmt.Errorf("解析默认日志配置失败: %w", err)
+		}
+	}
+	if utils.StringSliceContains(exclude, logger) {
+		return nil
+	}
+
+	if err := m.configManager.EnsurePathContext(ctx, LogsPath+"/"+DefaultLogName); err != nil {
+		return err
+	}
+	return m.configManager.SetConfigPathContext(ctx, excludePath, append(exclude, logger))
+}
@@ -0,0 +1,187 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword 使用 bcrypt 生成 basic auth 所需的密码哈希
+func HashPassword(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", fmt.Errorf("密码不能为空")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(plaintext), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("生成密码哈希失败: %w", err)
+	}
+	return string(hash), nil
+}
+
+// BasicAuthHandler 生成使用 http_basic 提供者的 authentication 处理器
+// bcryptHash 必须是 bcrypt 哈希 (可由 HashPassword 生成)，不接受明文密码
+func BasicAuthHandler(username, bcryptHash string) (types.Handler, error) {
+	if username == "" {
+		return types.Handler{}, fmt.Errorf("用户名不能为空")
+	}
+	if _, err := bcrypt.Cost([]byte(bcryptHash)); err != nil {
+		return types.Handler{}, fmt.Errorf("密码必须是 bcrypt 哈希: %w", err)
+	}
+
+	return types.Handler{
+		Handler: "authentication",
+		Providers: &types.AuthProviders{
+			HTTPBasic: &types.HTTPBasicAuth{
+				Accounts: []types.BasicAuthAccount{{Username: username, Password: bcryptHash}},
+				Hash:     &types.AuthHash{Algorithm: "bcrypt"},
+			},
+		},
+	}, nil
+}
+
+// WithBasicAuth 为路由加上 basic auth 认证，返回修改后的路由
+// 认证处理器插入到第一个生成响应的处理器之前，确保请求先经过认证；
+// 路由已有 authentication 处理器时将账号合并到其 http_basic 提供者中，同名账号的密码被替换
+func WithBasicAuth(route types.Route, username, bcryptHash string) (types.Route, error) {
+	handler, err := BasicAuthHandler(username, bcryptHash)
+	if err != nil {
+		return types.Route{}, err
+	}
+
+	handlers := append([]types.Handler(nil), route.Handle...)
+	for i := range handlers {
+		if handlers[i].Handler != "authentication" {
+			continue
+		}
+		providers := types.AuthProviders{}
+		if handlers[i].Providers != nil {
+			providers = *handlers[i].Providers
+		}
+		basic := *handler.Providers.HTTPBasic
+		if providers.HTTPBasic != nil {
+			basic = *providers.HTTPBasic
+			if basic.Hash != nil && !isBcrypt(basic.Hash.Algorithm) {
+				return types.Route{}, fmt.Errorf("路由已有的 http_basic 使用 %s 哈希，无法添加 bcrypt 密码", basic.Hash.Algorithm)
+			}
+			basic.Accounts = mergeBasicAccount(basic.Accounts, username, bcryptHash)
+		}
+		providers.HTTPBasic = &basic
+		handlers[i].Providers = &providers
+		route.Handle = handlers
+		return route, nil
+	}
+
+	route.Handle = insertBefore(handlers, handler)
+	return route, nil
+}
+
+// EnableBasicAuth 为已有路由启用 basic auth 认证
+// 路由已有 authentication 处理器时将账号合并到其 http_basic 提供者中（同名账号的密码被替换），
+// 处理器的其他配置保持不变
+func (m *Manager) EnableBasicAuth(routeID, username, bcryptHash string) error {
+	return m.EnableBasicAuthContext(context.Background(), routeID, username, bcryptHash)
+}
+
+// EnableBasicAuthContext 为已有路由启用 basic auth 认证（支持 context 取消和超时）
+func (m *Manager) EnableBasicAuthContext(ctx context.Context, routeID, username, bcryptHash string) error {
+	handler, err := BasicAuthHandler(username, bcryptHash)
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+
+	handlers, _ := route["handle"].([]interface{})
+	for i, h := range handlers {
+		existing, ok := h.(map[string]interface{})
+		if !ok || existing["handler"] != "authentication" {
+			continue
+		}
+
+		path := fmt.Sprintf("%s/handle/%d/providers", routeID, i)
+		providers, ok := existing["providers"].(map[string]interface{})
+		if !ok {
+			return m.client.PutByIDContext(ctx, handler.Providers, path, "PUT")
+		}
+		basic, ok := providers["http_basic"].(map[string]interface{})
+		if !ok {
+			return m.client.PutByIDContext(ctx, handler.Providers.HTTPBasic, path+"/http_basic", "PUT")
+		}
+		if hash, ok := basic["hash"].(map[string]interface{}); ok {
+			if algorithm, _ := hash["algorithm"].(string); !isBcrypt(algorithm) {
+				return fmt.Errorf("路由 %s 已有的 http_basic 使用 %s 哈希，无法添加 bcrypt 密码", routeID, algorithm)
+			}
+		}
+
+		// 只写回账号列表，realm、hash 等其他配置保持不变
+		accounts, _ := basic["accounts"].([]interface{})
+		merged := make([]interface{}, 0, len(accounts)+1)
+		replaced := false
+		for _, a := range accounts {
+			if account, ok := a.(map[string]interface{}); ok && account["username"] == username {
+				a = types.BasicAuthAccount{Username: username, Password: bcryptHash}
+				replaced = true
+			}
+			merged = append(merged, a)
+		}
+		if !replaced {
+			merged = append(merged, types.BasicAuthAccount{Username: username, Password: bcryptHash})
+		}
+
+		method := "PATCH"
+		if _, ok := basic["accounts"]; !ok {
+			method = "PUT"
+		}
+		return m.client.PutByIDContext(ctx, merged, path+"/http_basic/accounts", method)
+	}
+
+	return m.insertHandler(ctx, routeID, handler)
+}
+
+// mergeBasicAccount 将账号加入列表，已有同名账号时替换其密码
+func mergeBasicAccount(accounts []types.BasicAuthAccount, username, bcryptHash string) []types.BasicAuthAccount {
+	result := append([]types.BasicAuthAccount(nil), accounts...)
+	for i := range result {
+		if result[i].Username == username {
+			result[i].Password = bcryptHash
+			return result
+		}
+	}
+	return append(result, types.BasicAuthAccount{Username: username, Password: bcryptHash})
+}
+
+// isBcrypt 判断哈希算法是否为 bcrypt - 未指定时 Caddy 默认使用 bcrypt
+func isBcrypt(algorithm string) bool {
+	return algorithm == "" || algorithm == "bcrypt"
+}
+
+// insertBefore 将中间件处理器插入处理器列表，位于第一个生成响应的处理器之前
+// 与 insertHandler 的规则相同，列表中已有同类型处理器时原样返回
+func insertBefore(handlers []types.Handler, handler types.Handler) []types.Handler {
+	position := len(handlers)
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if handlers[i].Handler == handler.Handler {
+			return handlers
+		}
+		if utils.StringSliceContains(terminalHandlers, handlers[i].Handler) {
+			position = i
+		}
+	}
+
+	result := make([]types.Handler, 0, len(handlers)+1)
+	result = append(result, handlers[:position]...)
+	result = append(result, handler)
+	return append(result, handlers[position:]...)
+}
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHash 生成测试用的 bcrypt 哈希，使用最低成本以加快测试
+func testHash(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(hash)
+}
+
+func TestEnableBasicAuthReplacesAccount(t *testing.T) {
+	oldHash, newHash, opsHash := testHash(t, "old"), testHash(t, "new"), testHash(t, "ops")
+	m, srv := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"listen":[":443"],"routes":[
+		{"@id":"admin","handle":[
+			{"handler":"authentication","providers":{"http_basic":{"realm":"admin","accounts":[{"username":"admin","password":"`+oldHash+`"}]}}},
+			{"handler":"reverse_proxy","upstreams":[{"dial":"localhost:8080"}]}
+		]},
+		{"@id":"open","handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"localhost:8081"}]}]}
+	]}}}}}`)
+
+	if err := m.EnableBasicAuth("admin", "admin", newHash); err != nil {
+		t.Fatalf("EnableBasicAuth 失败: %v", err)
+	}
+	if err := m.EnableBasicAuth("admin", "ops", opsHash); err != nil {
+		t.Fatalf("EnableBasicAuth 添加账号失败: %v", err)
+	}
+
+	basic := RoutesPath + "/0/handle/0/providers/http_basic"
+	if got := srv.Get(basic + "/accounts/0/password"); got != newHash {
+		t.Errorf("admin 的密码哈希未被替换: %v", got)
+	}
+	if got := srv.Get(basic + "/accounts/1/username"); got != "ops" {
+		t.Errorf("新账号未追加: %v", got)
+	}
+	if got := srv.Get(basic + "/realm"); got != "admin" {
+		t.Errorf("realm 丢失: %v", got)
+	}
+	if got := len(srv.Get(RoutesPath + "/0/handle").([]interface{})); got != 2 {
+		t.Errorf("处理器数量 = %d, 期望 2", got)
+	}
+
+	if err := m.EnableBasicAuth("open", "admin", newHash); err != nil {
+		t.Fatalf("EnableBasicAuth 新建认证处理器失败: %v", err)
+	}
+	if got := srv.Get(RoutesPath + "/1/handle/0/handler"); got != "authentication" {
+		t.Errorf("认证处理器未插入到 reverse_proxy 之前: %v", got)
+	}
+}
+
+func TestEnableBasicAuthRejectsOtherHash(t *testing.T) {
+	m, _ := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"listen":[":443"],"routes":[
+		{"@id":"admin","handle":[{"handler":"authentication","providers":{"http_basic":{"hash":{"algorithm":"scrypt"},"accounts":[]}}}]}
+	]}}}}}`)
+
+	if err := m.EnableBasicAuth("admin", "admin", testHash(t, "new")); err == nil {
+		t.Error("期望已有 scrypt 配置时返回错误")
+	}
+}
+
+func TestWithBasicAuthReplacesAccount(t *testing.T) {
+	oldHash, newHash := testHash(t, "old"), testHash(t, "new")
+	route := types.Route{ID: "admin", Handle: []types.Handler{{Handler: "reverse_proxy"}}}
+
+	route, err := WithBasicAuth(route, "admin", oldHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := route
+	route, err = WithBasicAuth(route, "admin", newHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err = WithBasicAuth(route, "ops", oldHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(route.Handle) != 2 || route.Handle[0].Handler != "authentication" {
+		t.Fatalf("处理器 = %+v", route.Handle)
+	}
+	accounts := route.Handle[0].Providers.HTTPBasic.Accounts
+	if len(accounts) != 2 || accounts[0].Password != newHash || accounts[1].Username != "ops" {
+		t.Errorf("账号 = %+v", accounts)
+	}
+	if got := original.Handle[0].Providers.HTTPBasic.Accounts[0].Password; got != oldHash {
+		t.Error("WithBasicAuth 修改了传入的路由")
+	}
+}
@@ -0,0 +1,107 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// routeIndexPattern 匹配 Caddy 加载路由失败时错误信息中的路由序号 (如 "route 3: loading handler modules")
+var routeIndexPattern = regexp.MustCompile(`route (\d+):`)
+
+// BatchError 批量添加路由失败 - 指出导致失败的路由在提交数组中的位置
+type BatchError struct {
+	Index int               // 出错路由在提交数组中的索引，无法确定时为 -1
+	Route types.RouteConfig // 出错的路由，Index 为 -1 时为零值
+	Err   error             // Caddy 返回的原始错误
+}
+
+// Error 实现 error 接口
+func (e *BatchError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("批量添加路由失败: %v", e.Err)
+	}
+	return fmt.Sprintf("批量添加路由失败, 第 %d 个路由 (%s) 无效: %v", e.Index, e.Route.ID, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// AddRoutes 在一次请求中将多个路由追加到默认服务器
+// Caddy 以事务方式应用单次配置变更，因此要么全部生效，要么全部不生效；
+// 失败时返回 BatchError，指出导致失败的路由
+func (m *Manager) AddRoutes(routes ...types.RouteDefinition) error {
+	return m.AddRoutesContext(context.Background(), routes...)
+}
+
+// AddRoutesContext 在一次请求中将多个路由追加到默认服务器（支持 context 取消和超时）
+func (m *Manager) AddRoutesContext(ctx context.Context, routes ...types.RouteDefinition) error {
+	configs := make([]types.RouteConfig, 0, len(routes))
+	for i, route := range routes {
+		config, err := route.ToRouteConfig()
+		if err != nil {
+			return fmt.Errorf("转换第 %d 个路由失败: %w", i, err)
+		}
+		configs = append(configs, config)
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if len(configs) == 0 {
+		return nil
+	}
+
+	// 已有路由的数量，用于把 Caddy 错误中的路由序号换算为提交数组中的索引
+	data, err := m.configManager.GetConfigPathContext(ctx, RoutesPath)
+	if err != nil {
+		return err
+	}
+	var existing []json.RawMessage
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return fmt.Errorf("解析现有路由失败: %w", err)
+	}
+
+	// 路由数组不存在时整体设置，否则使用 "..." 一次追加所有元素
+	if existing == nil {
+		err = m.client.PutConfigContext(ctx, configs, RoutesPath, "PUT")
+	} else {
+		err = m.client.PutConfigContext(ctx, configs, RoutesPath+"/...", "POST")
+	}
+	if err != nil {
+		return newBatchError(err, configs, len(existing))
+	}
+	return nil
+}
+
+// newBatchError 根据 Caddy 的错误信息定位出错的路由
+func newBatchError(err error, routes []types.RouteConfig, offset int) error {
+	batchErr := &BatchError{Index: -1, Err: err}
+
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		return batchErr
+	}
+
+	match := routeIndexPattern.FindStringSubmatch(apiErr.Message)
+	if match == nil {
+		return batchErr
+	}
+	position, _ := strconv.Atoi(match[1])
+	if index := position - offset; index >= 0 && index < len(routes) {
+		batchErr.Index = index
+		batchErr.Route = routes[index]
+	}
+	return batchErr
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// 支持的压缩算法，顺序即默认的优先顺序
+var encodings = []string{"zstd", "gzip"}
+
+// 生成响应内容的处理器 - 中间件类处理器 (如 encode) 必须位于这些处理器之前才能生效
+var terminalHandlers = []string{"reverse_proxy", "file_server", "static_response", "subroute"}
+
+// EncodeHandler 生成 encode 处理器
+// opts.Encodings 为空时同时启用 zstd 和 gzip；MinimumLength 为 0 时使用 Caddy 默认值
+func EncodeHandler(opts types.EncodeOptions) (types.Handler, error) {
+	algorithms := opts.Encodings
+	if len(algorithms) == 0 {
+		algorithms = encodings
+	}
+	if opts.MinimumLength < 0 {
+		return types.Handler{}, fmt.Errorf("压缩的最小响应长度不能为负数")
+	}
+
+	enabled := make(map[string]struct{}, len(algorithms))
+	for _, name := range algorithms {
+		if !utils.StringSliceContains(encodings, name) {
+			return types.Handler{}, fmt.Errorf("不支持的压缩算法: %s", name)
+		}
+		enabled[name] = struct{}{}
+	}
+
+	return types.Handler{
+		Handler:       "encode",
+		Encodings:     enabled,
+		Prefer:        append([]string(nil), algorithms...),
+		MinimumLength: opts.MinimumLength,
+	}, nil
+}
+
+// EnableEncode 为已有路由启用响应压缩
+// encode 处理器插入到 reverse_proxy/file_server 等处理器之前；路由已有 encode 处理器时不做任何修改
+func (m *Manager) EnableEncode(routeID string, opts types.EncodeOptions) error {
+	return m.EnableEncodeContext(context.Background(), routeID, opts)
+}
+
+// EnableEncodeContext 为已有路由启用响应压缩（支持 context 取消和超时）
+func (m *Manager) EnableEncodeContext(ctx context.Context, routeID string, opts types.EncodeOptions) error {
+	handler, err := EncodeHandler(opts)
+	if err != nil {
+		return err
+	}
+	return m.insertHandler(ctx, routeID, handler)
+}
+
+// insertHandler 将中间件处理器插入路由的处理器链，位于第一个生成响应的处理器之前
+// 路由中已有同类型处理器时直接返回，保持幂等
+func (m *Manager) insertHandler(ctx context.Context, routeID string, handler types.Handler) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+
+	handlers, _ := route["handle"].([]interface{})
+	position := len(handlers)
+	for i := len(handlers) - 1; i >= 0; i-- {
+		h, ok := handlers[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := h["handler"].(string)
+		if name == handler.Handler {
+			return nil
+		}
+		if utils.StringSliceContains(terminalHandlers, name) {
+			position = i
+		}
+	}
+
+	// 对数组元素路径使用 PUT 会在该位置插入，对数组本身使用 POST 为追加
+	switch {
+	case position < len(handlers):
+		return m.client.PutByIDContext(ctx, handler, fmt.Sprintf("%s/handle/%d", routeID, position), "PUT")
+	case handlers == nil:
+		return m.client.PutByIDContext(ctx, []types.Handler{handler}, routeID+"/handle", "PUT")
+	default:
+		return m.client.PutByIDContext(ctx, handler, routeID+"/handle", "POST")
+	}
+}
@@ -0,0 +1,76 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// AddFileServer 添加静态文件服务路由到默认服务器
+// root 必须是 Caddy 所在主机上的绝对路径；设置 SPAFallback 时，
+// 请求的文件不存在则改写为回退文件，相当于 Caddyfile 中的 try_files {path} <fallback>
+func (m *Manager) AddFileServer(matchers []types.RouteMatch, root string, opts types.FileServerOptions) error {
+	return m.AddFileServerContext(context.Background(), matchers, root, opts)
+}
+
+// AddFileServerContext 添加静态文件服务路由（支持 context 取消和超时）
+func (m *Manager) AddFileServerContext(ctx context.Context, matchers []types.RouteMatch, root string, opts types.FileServerOptions) error {
+	route, err := FileServerRoute(matchers, root, opts)
+	if err != nil {
+		return err
+	}
+	return m.AddRouteToServerContext(ctx, DefaultServer, route)
+}
+
+// FileServerRoute 生成静态文件服务路由，不发送到 Caddy
+func FileServerRoute(matchers []types.RouteMatch, root string, opts types.FileServerOptions) (types.Route, error) {
+	// 远端的 Caddy 可能运行在不同的操作系统上，两种绝对路径形式都接受
+	if !path.IsAbs(root) && !filepath.IsAbs(root) {
+		return types.Route{}, fmt.Errorf("站点根目录必须是绝对路径: %s", root)
+	}
+	if opts.SPAFallback != "" && !strings.HasPrefix(opts.SPAFallback, "/") {
+		return types.Route{}, fmt.Errorf("单页应用回退文件必须以 / 开头: %s", opts.SPAFallback)
+	}
+
+	fileServer := types.Handler{
+		Handler:    "file_server",
+		Root:       root,
+		IndexNames: append([]string(nil), opts.IndexNames...),
+	}
+	if opts.Browse {
+		fileServer.Browse = &types.FileBrowse{}
+	}
+
+	handlers := []types.Handler{fileServer}
+	if opts.SPAFallback != "" {
+		// 与 Caddyfile 的 try_files 展开结果一致：文件匹配成功时改写到匹配到的文件
+		handlers = []types.Handler{
+			{
+				Handler: "subroute",
+				Routes: []types.Route{
+					{
+						Match: []types.RouteMatch{{
+							File: &types.FileMatcher{
+								Root:     root,
+								TryFiles: []string{"{http.request.uri.path}", opts.SPAFallback},
+							},
+						}},
+						Handle: []types.Handler{{Handler: "rewrite", URI: "{http.matchers.file.relative}"}},
+					},
+					{Handle: []types.Handler{fileServer}},
+				},
+			},
+		}
+	}
+
+	return types.Route{
+		ID:       opts.ID,
+		Match:    matchers,
+		Handle:   handlers,
+		Terminal: true,
+	}, nil
+}
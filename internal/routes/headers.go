@@ -0,0 +1,264 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// HeaderDownBuilder 响应头操作构建器 - 生成 headers 处理器的 response 配置块
+type HeaderDownBuilder struct {
+	ops types.RespHeaderOps
+	err error
+}
+
+// NewHeaderDown 创建新的响应头操作构建器
+func NewHeaderDown() *HeaderDownBuilder {
+	return &HeaderDownBuilder{}
+}
+
+// Set 设置响应头，覆盖已有的同名字段
+func (b *HeaderDownBuilder) Set(name, value string) *HeaderDownBuilder {
+	if !b.checkName(name) {
+		return b
+	}
+	if b.ops.Set == nil {
+		b.ops.Set = make(map[string][]string)
+	}
+	b.ops.Set[name] = []string{value}
+	return b
+}
+
+// Add 追加响应头，保留已有的同名字段
+func (b *HeaderDownBuilder) Add(name, value string) *HeaderDownBuilder {
+	if !b.checkName(name) {
+		return b
+	}
+	if b.ops.Add == nil {
+		b.ops.Add = make(map[string][]string)
+	}
+	b.ops.Add[name] = append(b.ops.Add[name], value)
+	return b
+}
+
+// Delete 删除响应头 (如 "Server")
+// 与 Caddyfile 的 header -Field 一致，删除操作会自动延迟到响应写出前执行，才能删除上游设置的字段
+func (b *HeaderDownBuilder) Delete(name string) *HeaderDownBuilder {
+	if !b.checkName(name) {
+		return b
+	}
+	if !utils.StringSliceContains(b.ops.Delete, name) {
+		b.ops.Delete = append(b.ops.Delete, name)
+	}
+	b.ops.Deferred = true
+	return b
+}
+
+// Deferred 将所有操作延迟到响应写出前执行
+// 需要覆盖 reverse_proxy 等后续处理器设置的响应头时使用
+func (b *HeaderDownBuilder) Deferred() *HeaderDownBuilder {
+	b.ops.Deferred = true
+	return b
+}
+
+// Build 生成 headers 处理器
+// 未设置任何操作或构建过程中出现无效参数时返回错误
+func (b *HeaderDownBuilder) Build() (types.Handler, error) {
+	if b.err != nil {
+		return types.Handler{}, b.err
+	}
+	if len(b.ops.Set) == 0 && len(b.ops.Add) == 0 && len(b.ops.Delete) == 0 {
+		return types.Handler{}, fmt.Errorf("未设置任何响应头操作")
+	}
+
+	response := types.RespHeaderOps{}
+	mergeResponseOps(&response, b.ops)
+	return types.Handler{Handler: "headers", Response: &response}, nil
+}
+
+// checkName 校验头部字段名，无效时记录错误
+func (b *HeaderDownBuilder) checkName(name string) bool {
+	if name == "" {
+		if b.err == nil {
+			b.err = fmt.Errorf("响应头名称不能为空")
+		}
+		return false
+	}
+	return true
+}
+
+// WithHeaderDown 为路由加上响应头操作，返回修改后的路由
+// 路由已有 headers 处理器时合并到其 response 配置中，否则在生成响应的处理器之前插入新的 headers 处理器
+func WithHeaderDown(route types.Route, headers *HeaderDownBuilder) (types.Route, error) {
+	handler, err := headers.Build()
+	if err != nil {
+		return types.Route{}, err
+	}
+
+	handlers := append([]types.Handler(nil), route.Handle...)
+	for i := range handlers {
+		if handlers[i].Handler != "headers" {
+			continue
+		}
+		response := types.RespHeaderOps{}
+		if handlers[i].Response != nil {
+			mergeResponseOps(&response, *handlers[i].Response)
+		}
+		mergeResponseOps(&response, *handler.Response)
+		handlers[i].Response = &response
+		route.Handle = handlers
+		return route, nil
+	}
+
+	route.Handle = insertBefore(handlers, handler)
+	return route, nil
+}
+
+// ApplyHeaderDown 为已有路由设置响应头操作
+// 路由已有 headers 处理器时合并到其 response 配置中（同名 Set 字段被覆盖，Add 和 Delete 追加），
+// 不会产生重复的 headers 处理器
+func (m *Manager) ApplyHeaderDown(routeID string, headers *HeaderDownBuilder) error {
+	return m.ApplyHeaderDownContext(context.Background(), routeID, headers)
+}
+
+// ApplyHeaderDownContext 为已有路由设置响应头操作（支持 context 取消和超时）
+func (m *Manager) ApplyHeaderDownContext(ctx context.Context, routeID string, headers *HeaderDownBuilder) error {
+	handler, err := headers.Build()
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+
+	handlers, _ := route["handle"].([]interface{})
+	for i, h := range handlers {
+		existing, ok := h.(map[string]interface{})
+		if !ok || existing["handler"] != "headers" {
+			continue
+		}
+
+		// 先按已知字段合并，再写回原始配置，response 中的其他字段 (如 require) 保持不变
+		response := types.RespHeaderOps{}
+		if raw, ok := existing["response"]; ok {
+			data, err := json.Marshal(raw)
+			if err != nil {
+				return fmt.Errorf("解析现有响应头配置失败: %w", err)
+			}
+			if err := json.Unmarshal(data, &response); err != nil {
+				return fmt.Errorf("解析现有响应头配置失败: %w", err)
+			}
+		}
+		mergeResponseOps(&response, *handler.Response)
+
+		path := fmt.Sprintf("%s/handle/%d/response", routeID, i)
+		if _, ok := existing["response"]; ok {
+			return m.client.PutByIDContext(ctx, mergedResponse(existing["response"], response), path, "PATCH")
+		}
+		return m.client.PutByIDContext(ctx, response, path, "PUT")
+	}
+
+	return m.insertHandler(ctx, routeID, handler)
+}
+
+// mergedResponse 将合并后的已知字段写回原始的 response 配置，保留其中的其他字段
+func mergedResponse(raw interface{}, response types.RespHeaderOps) map[string]interface{} {
+	result := make(map[string]interface{})
+	if existing, ok := raw.(map[string]interface{}); ok {
+		for key, value := range existing {
+			result[key] = value
+		}
+	}
+	if len(response.Set) > 0 {
+		result["set"] = response.Set
+	}
+	if len(response.Add) > 0 {
+		result["add"] = response.Add
+	}
+	if len(response.Delete) > 0 {
+		result["delete"] = response.Delete
+	}
+	if response.Deferred {
+		result["deferred"] = true
+	}
+	return result
+}
+
+// mergeResponseOps 将 src 的响应头操作合并到 dst
+// 字段名按 http.CanonicalHeaderKey 规范化后再比较，dst 中已有的字段名也会被规范化；
+// Set 同名字段以 src 为准，Add 的值追加，Delete 取并集，Deferred 任一为真即为真
+func mergeResponseOps(dst *types.RespHeaderOps, src types.RespHeaderOps) {
+	dst.Set = canonicalHeaders(dst.Set, false)
+	dst.Add = canonicalHeaders(dst.Add, true)
+	dst.Delete = canonicalNames(dst.Delete)
+
+	for name, values := range canonicalHeaders(src.Set, false) {
+		if dst.Set == nil {
+			dst.Set = make(map[string][]string)
+		}
+		dst.Set[name] = append([]string(nil), values...)
+	}
+	for name, values := range canonicalHeaders(src.Add, true) {
+		if dst.Add == nil {
+			dst.Add = make(map[string][]string)
+		}
+		dst.Add[name] = append(dst.Add[name], values...)
+	}
+	for _, name := range canonicalNames(src.Delete) {
+		if !utils.StringSliceContains(dst.Delete, name) {
+			dst.Delete = append(dst.Delete, name)
+		}
+	}
+	dst.Deferred = dst.Deferred || src.Deferred
+}
+
+// canonicalHeaders 返回字段名规范化后的头部映射
+// 多个写法规范化后相同时，join 为真则按字段名顺序合并取值，否则以已是规范写法的字段为准
+func canonicalHeaders(headers map[string][]string, join bool) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make(map[string][]string, len(headers))
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		_, taken := result[key]
+		switch {
+		case join:
+			result[key] = append(result[key], headers[name]...)
+		case !taken || name == key:
+			result[key] = append([]string(nil), headers[name]...)
+		}
+	}
+	return result
+}
+
+// canonicalNames 返回规范化并去重后的字段名列表，保持原有顺序
+func canonicalNames(names []string) []string {
+	var result []string
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		if !utils.StringSliceContains(result, key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+func TestMergeResponseOps(t *testing.T) {
+	ops := func(set, add map[string][]string, del ...string) types.RespHeaderOps {
+		return types.RespHeaderOps{HeaderOps: types.HeaderOps{Set: set, Add: add, Delete: del}}
+	}
+	tests := []struct {
+		name string
+		dst  types.RespHeaderOps
+		src  types.RespHeaderOps
+		want types.RespHeaderOps
+	}{
+		{
+			name: "into empty",
+			dst:  ops(nil, nil),
+			src:  ops(map[string][]string{"x-frame-options": {"DENY"}}, nil, "server"),
+			want: ops(map[string][]string{"X-Frame-Options": {"DENY"}}, nil, "Server"),
+		},
+		{
+			name: "set overrides lowercase existing",
+			dst:  ops(map[string][]string{"x-frame-options": {"SAMEORIGIN"}}, nil),
+			src:  ops(map[string][]string{"X-Frame-Options": {"DENY"}}, nil),
+			want: ops(map[string][]string{"X-Frame-Options": {"DENY"}}, nil),
+		},
+		{
+			name: "add appends across spellings",
+			dst:  ops(nil, map[string][]string{"vary": {"Origin"}}),
+			src:  ops(nil, map[string][]string{"VARY": {"Accept-Encoding"}}),
+			want: ops(nil, map[string][]string{"Vary": {"Origin", "Accept-Encoding"}}),
+		},
+		{
+			name: "delete deduplicated",
+			dst:  ops(nil, nil, "server", "X-Powered-By"),
+			src:  ops(nil, nil, "Server", "x-powered-by", "Via"),
+			want: ops(nil, nil, "Server", "X-Powered-By", "Via"),
+		},
+		{
+			name: "existing duplicates collapsed",
+			dst:  ops(map[string][]string{"x-a": {"1"}, "X-A": {"2"}}, map[string][]string{"x-b": {"1"}, "X-B": {"2"}}),
+			src:  ops(nil, nil),
+			want: ops(map[string][]string{"X-A": {"2"}}, map[string][]string{"X-B": {"2", "1"}}),
+		},
+		{
+			name: "deferred kept",
+			dst:  types.RespHeaderOps{Deferred: true},
+			src:  ops(nil, nil, "Server"),
+			want: types.RespHeaderOps{HeaderOps: types.HeaderOps{Delete: []string{"Server"}}, Deferred: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := tt.dst
+			mergeResponseOps(&dst, tt.src)
+			if !reflect.DeepEqual(dst, tt.want) {
+				t.Errorf("合并结果 = %+v, 期望 %+v", dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyHeaderDownMergesExistingSpelling(t *testing.T) {
+	m, srv := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"routes":[{"@id":"app","handle":[
+		{"handler":"headers","response":{"set":{"x-frame-options":["SAMEORIGIN"]},"delete":["server"],"require":{"status_code":[200]}}},
+		{"handler":"reverse_proxy","upstreams":[{"dial":"localhost:8080"}]}]}]}}}}}`)
+
+	headers := NewHeaderDown().Set("X-Frame-Options", "DENY").Delete("Server")
+	if err := m.ApplyHeaderDown("app", headers); err != nil {
+		t.Fatalf("ApplyHeaderDown 失败: %v", err)
+	}
+
+	response := srv.Get("/apps/http/servers/srv0/routes/0/handle/0/response").(map[string]interface{})
+	wantSet := map[string]interface{}{"X-Frame-Options": []interface{}{"DENY"}}
+	if !reflect.DeepEqual(response["set"], wantSet) {
+		t.Errorf("set = %v, 期望 %v", response["set"], wantSet)
+	}
+	if !reflect.DeepEqual(response["delete"], []interface{}{"Server"}) {
+		t.Errorf("delete = %v, 期望 [Server]", response["delete"])
+	}
+	if response["require"] == nil {
+		t.Error("response 中的其他字段被丢弃了")
+	}
+	if handlers := srv.Get("/apps/http/servers/srv0/routes/0/handle").([]interface{}); len(handlers) != 2 {
+		t.Errorf("handlers 数量 = %d, 不应插入新的 headers 处理器", len(handlers))
+	}
+}
@@ -0,0 +1,80 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+)
+
+// ListRouteIDs 列出所有服务器中的路由 @id
+// 包括 subroute 处理器中嵌套的路由和 errors.routes 中的错误处理路由；没有 @id 的路由会被跳过
+// 服务器按名称排序，同一服务器内按配置中的顺序返回；尚未初始化 HTTP 服务器时返回空列表
+func (m *Manager) ListRouteIDs() ([]string, error) {
+	return m.ListRouteIDsContext(context.Background())
+}
+
+// ListRouteIDsContext 列出所有服务器中的路由 @id（支持 context 取消和超时）
+func (m *Manager) ListRouteIDsContext(ctx context.Context) ([]string, error) {
+	data, err := m.client.GetRawConfigContext(ctx, ServersPath)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("获取服务器列表失败: %w", err)
+	}
+
+	// 不存在的键 Caddy 返回 null
+	var servers map[string]map[string]interface{}
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("解析服务器列表失败: %w", err)
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := []string{}
+	for _, name := range names {
+		ids = append(ids, collectRouteIDs(servers[name])...)
+	}
+	return ids, nil
+}
+
+// ListServerRouteIDs 列出指定服务器中的路由 @id，规则与 ListRouteIDs 相同
+// 服务器不存在时返回错误
+func (m *Manager) ListServerRouteIDs(serverName string) ([]string, error) {
+	return m.ListServerRouteIDsContext(context.Background(), serverName)
+}
+
+// ListServerRouteIDsContext 列出指定服务器中的路由 @id（支持 context 取消和超时）
+func (m *Manager) ListServerRouteIDsContext(ctx context.Context, serverName string) ([]string, error) {
+	server, err := m.getServer(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+	return collectRouteIDs(server), nil
+}
+
+// collectRouteIDs 收集服务器 routes 和 errors.routes 中所有路由的 @id
+func collectRouteIDs(server map[string]interface{}) []string {
+	ids := []string{}
+	collect := func(route map[string]interface{}) {
+		if id, ok := route["@id"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	routes, _ := server["routes"].([]interface{})
+	walkRoutes(routes, collect)
+
+	if errorsConfig, ok := server["errors"].(map[string]interface{}); ok {
+		errorRoutes, _ := errorsConfig["routes"].([]interface{})
+		walkRoutes(errorRoutes, collect)
+	}
+	return ids
+}
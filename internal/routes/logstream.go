@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// 常量定义 - 日志流参数
+const (
+	streamLogPrefix      = "fastcaddy_stream_"    // 日志流在 logging/logs 中的名称前缀
+	streamListenAddr     = "127.0.0.1:0"          // 在 Caddy 所在主机上监听的地址，端口由系统分配
+	streamMinBackoff     = 500 * time.Millisecond // 监听失败后首次重试前的等待时间
+	streamMaxBackoff     = 30 * time.Second       // 重试等待时间上限
+	streamCleanupTimeout = 10 * time.Second       // 结束时删除日志配置的超时时间
+)
+
+// StreamLogs 持续接收 Caddy 的日志，每收到一行 (一条 JSON 日志) 调用一次 handler
+// 在 Caddy 所在主机上监听本地端口（配置了 SSH 时通过 SSH 远程端口转发），并在 logging/logs 中
+// 添加一个 net 输出指向该端口的日志；Caddy 断开后会自行重连，监听失败 (如 SSH 连接断开) 时
+// 按指数退避重新监听并更新日志地址。连接断开时未以换行结尾的残余数据也会交给 handler。
+// handler 不会被并发调用；ctx 结束时删除该日志配置并返回 ctx 的错误
+func (m *Manager) StreamLogs(ctx context.Context, handler func(event []byte)) error {
+	name := fmt.Sprintf("%s%d", streamLogPrefix, time.Now().UnixNano())
+
+	var mu sync.Mutex
+	emit := func(line []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		handler(line)
+	}
+
+	registered := false
+	delay := streamMinBackoff
+	for {
+		configured, accepted, err := m.streamLogsOnce(ctx, name, emit)
+		registered = registered || configured
+		if ctx.Err() != nil {
+			break
+		}
+		// 从未成功配置过日志流通常是配置问题 (如 Caddy 不在本机且未配置 SSH)，直接返回
+		if !registered {
+			return fmt.Errorf("打开日志流失败: %w", err)
+		}
+
+		// 收到过 Caddy 的连接说明日志流曾经正常，重新从最短等待时间开始
+		if accepted {
+			delay = streamMinBackoff
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		delay *= 2
+		if delay > streamMaxBackoff {
+			delay = streamMaxBackoff
+		}
+	}
+
+	if registered {
+		if err := m.removeStreamLog(name); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// streamLogsOnce 监听一次并将日志指向该地址，直到监听器失败或 ctx 结束
+// 返回日志配置是否已写入 Caddy，以及是否收到过 Caddy 的连接
+func (m *Manager) streamLogsOnce(ctx context.Context, name string, emit func([]byte)) (bool, bool, error) {
+	listener, err := m.client.Listen("tcp", streamListenAddr)
+	if err != nil {
+		return false, false, err
+	}
+	if err := m.setStreamLog(ctx, name, listener.Addr().String()); err != nil {
+		listener.Close()
+		return false, false, err
+	}
+	accepted, err := serveLogStream(ctx, listener, emit)
+	return true, accepted, err
+}
+
+// setStreamLog 添加或更新指向 address 的 net 日志
+func (m *Manager) setStreamLog(ctx context.Context, name, address string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	log := types.CustomLog{
+		Writer: &types.LogWriter{
+			Output:    "net",
+			Address:   "tcp/" + address,
+			SoftStart: true,
+		},
+		Encoder: &types.LogEncoder{Format: "json"},
+	}
+	if err := m.configManager.EnsurePathContext(ctx, LogsPath); err != nil {
+		return err
+	}
+	if err := m.configManager.SetConfigPathContext(ctx, LogsPath+"/"+name, log); err != nil {
+		return fmt.Errorf("配置日志流 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// removeStreamLog 删除日志流的配置 - 调用时 ctx 已结束，使用独立的超时
+func (m *Manager) removeStreamLog(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), streamCleanupTimeout)
+	defer cancel()
+
+	err := m.client.PutConfigContext(ctx, nil, LogsPath+"/"+name, "DELETE")
+	if err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("删除日志流 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// serveLogStream 接收 Caddy 的连接并逐行读取日志，直到监听器失败或 ctx 结束
+// 返回前关闭监听器和所有连接，并等待读取完成，之后不会再调用 emit
+func serveLogStream(ctx context.Context, listener net.Listener, emit func([]byte)) (bool, error) {
+	var (
+		mu    sync.Mutex
+		conns = make(map[net.Conn]struct{})
+		wg    sync.WaitGroup
+	)
+
+	// ctx 结束时关闭监听器，使 Accept 返回
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer func() {
+		stop()
+		listener.Close()
+		mu.Lock()
+		for conn := range conns {
+			conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}()
+
+	accepted := false
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return accepted, err
+		}
+		accepted = true
+
+		mu.Lock()
+		conns[conn] = struct{}{}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readLogLines(conn, emit)
+			mu.Lock()
+			delete(conns, conn)
+			mu.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+// readLogLines 逐行读取日志直到连接结束，未以换行结尾的残余数据也会交给 emit
+func readLogLines(r io.Reader, emit func([]byte)) {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+			emit(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+)
+
+// waitFor 轮询直到 cond 返回 true，超时时测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	m, srv := newTestManager(t, emptyServer)
+
+	lines := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- m.StreamLogs(ctx, func(event []byte) { lines <- string(event) })
+	}()
+
+	// 模拟 Caddy 的 net 输出：按配置中的地址连接并写入日志
+	var address string
+	waitFor(t, "日志流配置", func() bool {
+		logs, _ := srv.Get(LogsPath).(map[string]interface{})
+		for name, log := range logs {
+			if !strings.HasPrefix(name, streamLogPrefix) {
+				continue
+			}
+			writer := log.(map[string]interface{})["writer"].(map[string]interface{})
+			if writer["output"] != "net" || writer["soft_start"] != true {
+				t.Fatalf("日志输出 = %v", writer)
+			}
+			address = strings.TrimPrefix(writer["address"].(string), "tcp/")
+		}
+		return address != ""
+	})
+	write := func(data string) {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			t.Fatalf("连接日志流失败: %v", err)
+		}
+		if _, err := conn.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+	}
+
+	var got []string
+	receive := func(n int) {
+		t.Helper()
+		for len(got) < n {
+			select {
+			case line := <-lines:
+				got = append(got, line)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("只收到 %v", got)
+			}
+		}
+	}
+	write("{\"msg\":\"a\"}\n{\"msg\":\"b\"}\r\n{\"msg\":\"partial\"}")
+	receive(3)
+	// 第二次连接模拟 Caddy 断线后重连
+	write("{\"msg\":\"c\"}\n")
+	receive(4)
+
+	want := []string{`{"msg":"a"}`, `{"msg":"b"}`, `{"msg":"partial"}`, `{"msg":"c"}`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("日志 = %v, 期望 %v", got, want)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamLogs 返回 %v, 期望 context.Canceled", err)
+	}
+	if logs, _ := srv.Get(LogsPath).(map[string]interface{}); len(logs) != 0 {
+		t.Errorf("结束后日志配置未删除: %v", logs)
+	}
+}
+
+func TestStreamLogsRequiresSSHForRemoteCaddy(t *testing.T) {
+	client, err := api.NewClientWithOptions(api.Options{BaseURL: "http://caddy.internal:2019"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManagerWithClient(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.StreamLogs(ctx, func([]byte) {}); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("StreamLogs 返回 %v, 期望立即返回需要 SSH 的错误", err)
+	}
+}
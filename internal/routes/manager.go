@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -11,8 +13,9 @@ import (
 
 // 常量定义 - 服务器和路由配置路径
 const (
-	ServersPath = "/apps/http/servers"
-	RoutesPath  = ServersPath + "/srv0/routes"
+	DefaultServer = "srv0"
+	ServersPath   = "/apps/http/servers"
+	RoutesPath    = ServersPath + "/" + DefaultServer + "/routes"
 )
 
 // Manager 路由管理器 - 处理路由相关配置
@@ -23,22 +26,39 @@ type Manager struct {
 
 // NewManager 创建新的路由管理器
 func NewManager() *Manager {
+	return NewManagerWithClient(api.NewClient())
+}
+
+// NewManagerWithClient 使用指定的 API 客户端创建路由管理器
+// 所有子管理器共享同一个客户端，从而共享连接方式和传输层
+func NewManagerWithClient(client *api.Client) *Manager {
 	return &Manager{
-		client:        api.NewClient(),
-		configManager: config.NewManager(),
+		client:        client,
+		configManager: config.NewManagerWithClient(client),
 	}
 }
 
 // InitRoutes 初始化 HTTP 路由配置 - 对应 Python 的 init_routes(srv_name, skip) 函数
 // 创建基础的 HTTP 服务器和路由配置
 func (m *Manager) InitRoutes(serverName string, skip int) error {
+	return m.InitRoutesContext(context.Background(), serverName, skip)
+}
+
+// InitRoutesContext 初始化 HTTP 路由配置（支持 context 取消和超时）
+func (m *Manager) InitRoutesContext(ctx context.Context, serverName string, skip int) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 如果服务器路径已存在，直接返回
-	if m.client.HasPath(ServersPath) {
+	if m.client.HasPathContext(ctx, ServersPath) {
 		return nil
 	}
 
 	// 初始化服务器路径
-	if err := m.configManager.InitPath(ServersPath, skip); err != nil {
+	if err := m.configManager.InitPathContext(ctx, ServersPath, skip); err != nil {
 		return err
 	}
 
@@ -50,30 +70,160 @@ func (m *Manager) InitRoutes(serverName string, skip int) error {
 	}
 
 	// 设置服务器配置
-	serverPath := fmt.Sprintf("%s/%s", ServersPath, serverName)
-	return m.client.PutConfig(serverConfig, serverPath, "POST")
+	return m.client.PutConfigContext(ctx, serverConfig, ServerPath(serverName), "POST")
 }
 
 // AddRoute 添加路由规则 - 对应 Python 的 add_route(route) 函数
-// 将路由配置添加到 Caddy 服务器
-func (m *Manager) AddRoute(route types.Route) error {
-	return m.client.PutConfig(route, RoutesPath, "POST")
+// 将路由添加到默认服务器；route 可以是类型化的 types.Route，也可以是 GetRoute 返回的 types.RouteConfig，
+// 后者的处理器和匹配集合按原样发送，修改后可直接重新添加
+func (m *Manager) AddRoute(route types.RouteDefinition) error {
+	return m.AddRouteContext(context.Background(), route)
+}
+
+// AddRouteContext 添加路由规则（支持 context 取消和超时）
+func (m *Manager) AddRouteContext(ctx context.Context, route types.RouteDefinition) error {
+	config, err := route.ToRouteConfig()
+	if err != nil {
+		return err
+	}
+	return m.client.PutConfigContext(ctx, config, RoutesPath, "POST")
+}
+
+// AddRouteToServer 将路由添加到指定名称的服务器
+// 服务器不存在时返回错误，不会自动创建新服务器
+func (m *Manager) AddRouteToServer(serverName string, route types.RouteDefinition) error {
+	return m.AddRouteToServerContext(context.Background(), serverName, route)
+}
+
+// AddRouteToServerContext 将路由添加到指定名称的服务器（支持 context 取消和超时）
+func (m *Manager) AddRouteToServerContext(ctx context.Context, serverName string, route types.RouteDefinition) error {
+	config, err := route.ToRouteConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	server, err := m.getServer(ctx, serverName)
+	if err != nil {
+		return err
+	}
+
+	routesPath := ServerRoutesPath(serverName)
+
+	// 服务器尚无 routes 数组时，直接设置为只包含该路由的数组
+	if _, ok := server["routes"]; !ok {
+		return m.client.PutConfigContext(ctx, []types.RouteConfig{config}, routesPath, "PUT")
+	}
+	return m.client.PutConfigContext(ctx, config, routesPath, "POST")
+}
+
+// ServerPath 返回指定服务器的配置路径
+func ServerPath(serverName string) string {
+	return fmt.Sprintf("%s/%s", ServersPath, serverName)
+}
+
+// ServerRoutesPath 返回指定服务器的路由列表路径
+func ServerRoutesPath(serverName string) string {
+	return ServerPath(serverName) + "/routes"
+}
+
+// getServer 获取指定服务器的配置，服务器不存在时返回明确的错误
+func (m *Manager) getServer(ctx context.Context, serverName string) (map[string]interface{}, error) {
+	if serverName == "" {
+		return nil, fmt.Errorf("服务器名称不能为空")
+	}
+
+	data, err := m.client.GetRawConfigContext(ctx, ServerPath(serverName))
+	if err != nil {
+		if api.IsNotFound(err) {
+			return nil, fmt.Errorf("服务器 %s 不存在", serverName)
+		}
+		return nil, err
+	}
+
+	// 不存在的键 Caddy 返回 null
+	var server map[string]interface{}
+	if err := json.Unmarshal(data, &server); err != nil {
+		return nil, fmt.Errorf("解析服务器 %s 配置失败: %w", serverName, err)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("服务器 %s 不存在", serverName)
+	}
+
+	return server, nil
 }
 
 // DeleteByID 删除指定 ID 的路由 - 对应 Python 的 del_id(id) 函数
-// 通过路由 ID 删除特定路由
+// 通过 /id/<id> 按 @id 定位路由，与其在数组中的位置无关，不会影响其他路由
+// 路由不存在时返回的错误满足 errors.Is(err, api.ErrNotFound)，调用方可视为已删除
 func (m *Manager) DeleteByID(id string) error {
-	return m.client.DeleteByID(id)
+	return m.DeleteByIDContext(context.Background(), id)
+}
+
+// DeleteByIDContext 删除指定 ID 的路由（支持 context 取消和超时）
+func (m *Manager) DeleteByIDContext(ctx context.Context, id string) error {
+	return m.client.DeleteByIDContext(ctx, id)
+}
+
+// GetRoute 通过 @id 获取单个路由
+// 通过 /id/<id> 直接访问，无需拉取整个配置；返回值可原样传给 AddRoute
+func (m *Manager) GetRoute(id string) (types.RouteConfig, error) {
+	return m.GetRouteContext(context.Background(), id)
+}
+
+// GetRouteContext 通过 @id 获取单个路由（支持 context 取消和超时）
+func (m *Manager) GetRouteContext(ctx context.Context, id string) (types.RouteConfig, error) {
+	data, err := m.client.GetRawByIDContext(ctx, id)
+	if err != nil {
+		return types.RouteConfig{}, err
+	}
+
+	var route types.RouteConfig
+	if err := json.Unmarshal(data, &route); err != nil {
+		return types.RouteConfig{}, fmt.Errorf("解析路由 %s 失败: %w", id, err)
+	}
+	return route, nil
+}
+
+// RouteExists 检查指定 @id 的路由是否存在
+// 路由不存在 (404) 时返回 false 而不是错误，其他失败仍返回错误
+func (m *Manager) RouteExists(id string) (bool, error) {
+	return m.RouteExistsContext(context.Background(), id)
+}
+
+// RouteExistsContext 检查指定 @id 的路由是否存在（支持 context 取消和超时）
+func (m *Manager) RouteExistsContext(ctx context.Context, id string) (bool, error) {
+	if _, err := m.client.GetRawByIDContext(ctx, id); err != nil {
+		if api.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 // AddReverseProxy 添加反向代理路由 - 对应 Python 的 add_reverse_proxy(from_host, to_url) 函数
 // 创建从指定主机到目标 URL 的反向代理
 func (m *Manager) AddReverseProxy(fromHost, toURL string) error {
+	return m.AddReverseProxyContext(context.Background(), fromHost, toURL)
+}
+
+// AddReverseProxyContext 添加反向代理路由（支持 context 取消和超时）
+func (m *Manager) AddReverseProxyContext(ctx context.Context, fromHost, toURL string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 如果已存在相同主机的路由，先删除
-	if m.client.HasID(fromHost) {
-		if err := m.client.DeleteByID(fromHost); err != nil {
-			return fmt.Errorf("删除现有路由失败: %w", err)
-		}
+	if err := m.client.DeleteByIDContext(ctx, fromHost); err != nil && !api.IsNotFound(err) {
+		return fmt.Errorf("删除现有路由失败: %w", err)
 	}
 
 	// 创建反向代理路由配置
@@ -98,12 +248,17 @@ func (m *Manager) AddReverseProxy(fromHost, toURL string) error {
 	}
 
 	// 添加路由
-	return m.AddRoute(route)
+	return m.AddRouteContext(ctx, route)
 }
 
 // AddWildcardRoute 添加通配符子域名路由 - 对应 Python 的 add_wildcard_route(domain) 函数
 // 为指定域名创建通配符子域名路由
 func (m *Manager) AddWildcardRoute(domain string) error {
+	return m.AddWildcardRouteContext(context.Background(), domain)
+}
+
+// AddWildcardRouteContext 添加通配符子域名路由（支持 context 取消和超时）
+func (m *Manager) AddWildcardRouteContext(ctx context.Context, domain string) error {
 	// 创建通配符路由配置
 	route := types.Route{
 		ID: fmt.Sprintf("wildcard-%s", domain),
@@ -122,12 +277,17 @@ func (m *Manager) AddWildcardRoute(domain string) error {
 	}
 
 	// 添加路由
-	return m.AddRoute(route)
+	return m.AddRouteContext(ctx, route)
 }
 
 // AddSubReverseProxy 添加子域名反向代理 - 对应 Python 的 add_sub_reverse_proxy 函数
 // 为通配符域名下的特定子域名添加反向代理，支持多端口
 func (m *Manager) AddSubReverseProxy(domain, subdomain string, ports []string, host string) error {
+	return m.AddSubReverseProxyContext(context.Background(), domain, subdomain, ports, host)
+}
+
+// AddSubReverseProxyContext 添加子域名反向代理（支持 context 取消和超时）
+func (m *Manager) AddSubReverseProxyContext(ctx context.Context, domain, subdomain string, ports []string, host string) error {
 	wildcardID := fmt.Sprintf("wildcard-%s", domain)
 	routeID := fmt.Sprintf("%s.%s", subdomain, domain)
 
@@ -163,12 +323,17 @@ func (m *Manager) AddSubReverseProxy(domain, subdomain string, ports []string, h
 	// 将子路由添加到通配符路由的处理器中
 	// 这里使用 "..." 语法来追加到现有路由列表
 	subroutePath := fmt.Sprintf("%s/handle/0/routes/...", wildcardID)
-	return m.client.PutByID([]types.Route{newRoute}, subroutePath, "POST")
+	return m.client.PutByIDContext(ctx, []types.Route{newRoute}, subroutePath, "POST")
 }
 
 // AddSubReverseProxyWithPorts 添加子域名反向代理（支持单个端口或端口列表）
 // 这是一个便利方法，可以接受不同类型的端口参数
 func (m *Manager) AddSubReverseProxyWithPorts(domain, subdomain string, ports interface{}, host string) error {
+	return m.AddSubReverseProxyWithPortsContext(context.Background(), domain, subdomain, ports, host)
+}
+
+// AddSubReverseProxyWithPortsContext 添加子域名反向代理（支持 context 取消和超时）
+func (m *Manager) AddSubReverseProxyWithPortsContext(ctx context.Context, domain, subdomain string, ports interface{}, host string) error {
 	var portList []string
 
 	// 处理不同类型的端口参数
@@ -198,5 +363,5 @@ func (m *Manager) AddSubReverseProxyWithPorts(domain, subdomain string, ports in
 		return fmt.Errorf("不支持的端口类型: %T", ports)
 	}
 
-	return m.AddSubReverseProxy(domain, subdomain, portList, host)
+	return m.AddSubReverseProxyContext(ctx, domain, subdomain, portList, host)
 }
@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/caddytest"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// newTestManager 创建连接到模拟服务器的路由管理器
+func newTestManager(t *testing.T, initial string) (*Manager, *caddytest.Server) {
+	t.Helper()
+	srv := caddytest.NewServer(t, initial)
+	client, err := api.NewClientWithOptions(api.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	return NewManagerWithClient(client), srv
+}
+
+const emptyServer = `{"apps":{"http":{"servers":{"srv0":{"listen":[":443"],"routes":[]}}}}}`
+
+func TestAddRouteFromGetRoute(t *testing.T) {
+	m, srv := newTestManager(t, emptyServer)
+
+	route, err := types.NewRouteConfig(types.Route{
+		ID:     "app",
+		Match:  []types.RouteMatch{{Host: []string{"app.example.com"}}},
+		Handle: []types.Handler{{Handler: "reverse_proxy", Upstreams: []types.Upstream{{Dial: "localhost:8080"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddRoute(route); err != nil {
+		t.Fatalf("AddRoute 失败: %v", err)
+	}
+
+	got, err := m.GetRoute("app")
+	if err != nil {
+		t.Fatalf("GetRoute 失败: %v", err)
+	}
+	got.ID = "app-copy"
+	got.Terminal = true
+	if err := m.AddRoute(got); err != nil {
+		t.Fatalf("重新添加 GetRoute 的结果失败: %v", err)
+	}
+
+	routes := srv.Get(RoutesPath).([]interface{})
+	if len(routes) != 2 {
+		t.Fatalf("routes = %v", routes)
+	}
+	copied := routes[1].(map[string]interface{})
+	if copied["@id"] != "app-copy" || copied["terminal"] != true || copied["handle"] == nil {
+		t.Errorf("复制的路由 = %v", copied)
+	}
+}
+
+func TestRouteDefinitionsAccepted(t *testing.T) {
+	typed := types.Route{
+		ID:     "typed",
+		Match:  []types.RouteMatch{{Host: []string{"typed.example.com"}}},
+		Handle: []types.Handler{{Handler: "reverse_proxy", Upstreams: []types.Upstream{{Dial: "localhost:8080"}}}},
+	}
+	raw := types.RouteConfig{
+		ID:     "raw",
+		Handle: []json.RawMessage{json.RawMessage(`{"handler":"vars","custom":"kept"}`)},
+	}
+
+	m, srv := newTestManager(t, emptyServer)
+	if err := m.AddRoute(typed); err != nil {
+		t.Fatalf("AddRoute(types.Route) 失败: %v", err)
+	}
+	if err := m.AddRoutes(raw, types.Route{ID: "batch"}); err != nil {
+		t.Fatalf("AddRoutes 混合类型失败: %v", err)
+	}
+	if err := m.AddRouteToServer(DefaultServer, types.RouteConfig{ID: "server"}); err != nil {
+		t.Fatalf("AddRouteToServer(types.RouteConfig) 失败: %v", err)
+	}
+
+	got, err := m.GetRoute("raw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Terminal = true
+	if created, err := m.UpsertRoute("raw", got); err != nil || created {
+		t.Fatalf("UpsertRoute(types.RouteConfig) = %v, %v, 期望替换已有路由", created, err)
+	}
+
+	var ids []string
+	for _, r := range srv.Get(RoutesPath).([]interface{}) {
+		ids = append(ids, r.(map[string]interface{})["@id"].(string))
+	}
+	if want := []string{"typed", "raw", "batch", "server"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("路由顺序 = %v, 期望 %v", ids, want)
+	}
+	if got := srv.Get(RoutesPath + "/1/handle/0/custom"); got != "kept" {
+		t.Errorf("RouteConfig 的未知字段丢失: %v", got)
+	}
+	if got := srv.Get(RoutesPath + "/1/terminal"); got != true {
+		t.Errorf("UpsertRoute 未替换路由: terminal = %v", got)
+	}
+}
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// ClientAuthRequireAndVerify 要求客户端提供由信任的 CA 签发的证书
+const ClientAuthRequireAndVerify = "require_and_verify"
+
+// RequireClientCert 为服务器上的指定主机名启用双向 TLS，要求客户端提供由 caPEM 中的 CA 签发的证书
+// 在 tls_connection_policies 中添加按 SNI 匹配的连接策略，插入到第一个不限主机名的策略之前；
+// 其他主机名仍使用不限主机名的策略，不要求客户端证书。主机名完全相同的策略已存在时只替换其客户端证书校验配置
+func (m *Manager) RequireClientCert(serverName string, hosts []string, caPEM []byte) error {
+	return m.RequireClientCertContext(context.Background(), serverName, hosts, caPEM)
+}
+
+// RequireClientCertContext 为服务器上的指定主机名启用双向 TLS（支持 context 取消和超时）
+func (m *Manager) RequireClientCertContext(ctx context.Context, serverName string, hosts []string, caPEM []byte) error {
+	if len(hosts) == 0 {
+		return fmt.Errorf("至少需要指定一个主机名")
+	}
+	auth, err := ClientAuthentication(caPEM)
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	server, err := m.getServer(ctx, serverName)
+	if err != nil {
+		return err
+	}
+
+	// 保留原有策略的其他字段，只解析匹配条件
+	var policies []struct {
+		Match *types.ConnectionMatch `json:"match"`
+	}
+	if raw, ok := server["tls_connection_policies"]; ok {
+		data, _ := json.Marshal(raw)
+		if err := json.Unmarshal(data, &policies); err != nil {
+			return fmt.Errorf("解析服务器 %s 的 TLS 连接策略失败: %w", serverName, err)
+		}
+	}
+
+	policiesPath := ServerPath(serverName) + "/tls_connection_policies"
+	policy := types.TLSConnectionPolicy{
+		Match:                &types.ConnectionMatch{SNI: append([]string(nil), hosts...)},
+		ClientAuthentication: &auth,
+	}
+
+	if len(policies) == 0 {
+		// 只存在限定主机名的策略时其他连接无法握手，因此同时添加不限主机名的默认策略
+		return m.configManager.SetConfigPathContext(ctx, policiesPath, []types.TLSConnectionPolicy{policy, {}})
+	}
+
+	for i, existing := range policies {
+		if existing.Match != nil && sameHosts(existing.Match.SNI, hosts) {
+			return m.configManager.SetConfigPathContext(ctx, fmt.Sprintf("%s/%d/client_authentication", policiesPath, i), auth)
+		}
+	}
+	for i, existing := range policies {
+		// 对数组元素路径使用 PUT 会在该位置插入
+		if existing.Match == nil || len(existing.Match.SNI) == 0 {
+			return m.client.PutConfigContext(ctx, policy, fmt.Sprintf("%s/%d", policiesPath, i), "PUT")
+		}
+	}
+	// 没有不限主机名的策略时，使用 "..." 一次追加该策略和默认策略
+	return m.client.PutConfigContext(ctx, []types.TLSConnectionPolicy{policy, {}}, policiesPath+"/...", "POST")
+}
+
+// ClientAuthentication 根据 PEM 格式的 CA 证书生成 require_and_verify 模式的客户端证书校验配置
+// caPEM 可以包含多个证书，每个证书都会被信任；不包含有效证书时返回错误
+func ClientAuthentication(caPEM []byte) (types.ClientAuthentication, error) {
+	var certs []string
+	for rest := caPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return types.ClientAuthentication{}, fmt.Errorf("无效的 CA 证书: %w", err)
+		}
+		certs = append(certs, base64.StdEncoding.EncodeToString(block.Bytes))
+	}
+	if len(certs) == 0 {
+		return types.ClientAuthentication{}, fmt.Errorf("未找到 PEM 格式的 CA 证书")
+	}
+
+	return types.ClientAuthentication{
+		TrustedCACerts: certs,
+		Mode:           ClientAuthRequireAndVerify,
+	}, nil
+}
+
+// sameHosts 判断两组主机名是否相同，与顺序无关
+func sameHosts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x, y := append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
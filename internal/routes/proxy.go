@@ -0,0 +1,247 @@
+package routes
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// 支持的上游 HTTP 版本 - 对应 Caddy http 传输层的 versions 配置
+var transportVersions = []string{"1.1", "2", "h2c", "3"}
+
+// 支持的负载均衡策略 - 不需要额外参数的 Caddy 选择策略
+var selectionPolicies = []string{
+	"round_robin", "least_conn", "ip_hash", "random",
+	"first", "client_ip_hash", "uri_hash",
+}
+
+// ReverseProxyBuilder 反向代理处理器构建器 - 以链式调用代替手写 JSON
+// 构建结果与 Caddy 自身序列化的 reverse_proxy 处理器一致
+type ReverseProxyBuilder struct {
+	upstreams     []types.Upstream
+	headerUp      map[string][]string
+	flushInterval types.Duration
+	loadBalancing *types.LoadBalancing
+	healthChecks  *types.HealthChecks
+	transport     *types.HTTPTransport
+	err           error
+}
+
+// NewReverseProxy 创建新的反向代理构建器
+func NewReverseProxy() *ReverseProxyBuilder {
+	return &ReverseProxyBuilder{}
+}
+
+// Upstream 添加上游服务器地址 (如 "localhost:8080")
+func (b *ReverseProxyBuilder) Upstream(dial string) *ReverseProxyBuilder {
+	if dial == "" {
+		b.setErr(fmt.Errorf("上游地址不能为空"))
+		return b
+	}
+	b.upstreams = append(b.upstreams, types.Upstream{Dial: dial})
+	return b
+}
+
+// HeaderUp 设置发往上游的请求头，支持 Caddy 占位符 (如 "{http.request.host}")
+func (b *ReverseProxyBuilder) HeaderUp(name, value string) *ReverseProxyBuilder {
+	if name == "" {
+		b.setErr(fmt.Errorf("请求头名称不能为空"))
+		return b
+	}
+	if b.headerUp == nil {
+		b.headerUp = make(map[string][]string)
+	}
+	b.headerUp[name] = []string{value}
+	return b
+}
+
+// FlushInterval 设置响应刷新间隔，-1 表示每次写入后立即刷新
+func (b *ReverseProxyBuilder) FlushInterval(d time.Duration) *ReverseProxyBuilder {
+	b.flushInterval = types.Duration(d)
+	return b
+}
+
+// LoadBalancing 设置负载均衡策略及选择上游的重试时间
+// policy 可选值: round_robin, least_conn, ip_hash, random, first, client_ip_hash, uri_hash
+// tryDuration 为 0 时不重试；tryInterval 为 0 时使用 Caddy 默认值
+func (b *ReverseProxyBuilder) LoadBalancing(policy string, tryDuration, tryInterval time.Duration) *ReverseProxyBuilder {
+	if !utils.StringSliceContains(selectionPolicies, policy) {
+		b.setErr(fmt.Errorf("不支持的负载均衡策略: %s", policy))
+		return b
+	}
+	if tryDuration < 0 || tryInterval < 0 {
+		b.setErr(fmt.Errorf("负载均衡重试时间不能为负数"))
+		return b
+	}
+	b.loadBalancing = &types.LoadBalancing{
+		SelectionPolicy: &types.SelectionPolicy{Policy: policy},
+		TryDuration:     types.Duration(tryDuration),
+		TryInterval:     types.Duration(tryInterval),
+	}
+	return b
+}
+
+// ActiveHealthCheck 启用主动健康检查，按 interval 定期请求上游的 path
+// expectStatus 为 0 时接受任意 2xx 响应
+func (b *ReverseProxyBuilder) ActiveHealthCheck(path string, interval time.Duration, expectStatus int) *ReverseProxyBuilder {
+	if path == "" {
+		b.setErr(fmt.Errorf("健康检查路径不能为空"))
+		return b
+	}
+	if interval < 0 {
+		b.setErr(fmt.Errorf("健康检查间隔不能为负数"))
+		return b
+	}
+	if expectStatus < 0 || expectStatus > 599 {
+		b.setErr(fmt.Errorf("无效的期望状态码: %d", expectStatus))
+		return b
+	}
+	b.ensureHealthChecks().Active = &types.ActiveHealthChecks{
+		URI:          path,
+		Interval:     types.Duration(interval),
+		ExpectStatus: expectStatus,
+	}
+	return b
+}
+
+// PassiveHealthCheck 启用被动健康检查
+// 上游在 failDuration 内失败超过 maxFails 次后被标记为不健康
+func (b *ReverseProxyBuilder) PassiveHealthCheck(failDuration time.Duration, maxFails int) *ReverseProxyBuilder {
+	if failDuration <= 0 {
+		b.setErr(fmt.Errorf("被动健康检查的失败保留时间必须大于 0"))
+		return b
+	}
+	if maxFails < 1 {
+		b.setErr(fmt.Errorf("被动健康检查的最大失败次数必须大于 0"))
+		return b
+	}
+	b.ensureHealthChecks().Passive = &types.PassiveHealthChecks{
+		FailDuration: types.Duration(failDuration),
+		MaxFails:     maxFails,
+	}
+	return b
+}
+
+// DialTimeout 设置连接上游的超时时间
+func (b *ReverseProxyBuilder) DialTimeout(d time.Duration) *ReverseProxyBuilder {
+	return b.setTimeout("连接", d, func(t *types.HTTPTransport) { t.DialTimeout = types.Duration(d) })
+}
+
+// ReadTimeout 设置读取上游响应的超时时间
+func (b *ReverseProxyBuilder) ReadTimeout(d time.Duration) *ReverseProxyBuilder {
+	return b.setTimeout("读取", d, func(t *types.HTTPTransport) { t.ReadTimeout = types.Duration(d) })
+}
+
+// WriteTimeout 设置向上游写入请求的超时时间
+func (b *ReverseProxyBuilder) WriteTimeout(d time.Duration) *ReverseProxyBuilder {
+	return b.setTimeout("写入", d, func(t *types.HTTPTransport) { t.WriteTimeout = types.Duration(d) })
+}
+
+// ResponseHeaderTimeout 设置发送请求后等待上游响应头的超时时间
+func (b *ReverseProxyBuilder) ResponseHeaderTimeout(d time.Duration) *ReverseProxyBuilder {
+	return b.setTimeout("响应头", d, func(t *types.HTTPTransport) { t.ResponseHeaderTimeout = types.Duration(d) })
+}
+
+// Versions 设置与上游通信允许的 HTTP 版本，可选值: 1.1, 2, h2c, 3
+// 未设置时 Caddy 默认使用 1.1 和 2；h2c 表示不加密的 HTTP/2
+func (b *ReverseProxyBuilder) Versions(versions ...string) *ReverseProxyBuilder {
+	if len(versions) == 0 {
+		b.setErr(fmt.Errorf("HTTP 版本不能为空"))
+		return b
+	}
+	for _, v := range versions {
+		if !utils.StringSliceContains(transportVersions, v) {
+			b.setErr(fmt.Errorf("不支持的 HTTP 版本: %s", v))
+			return b
+		}
+	}
+	b.ensureTransport().Versions = append([]string(nil), versions...)
+	return b
+}
+
+// H2C 通过不加密的 HTTP/2 (h2c) 连接上游，适用于 gRPC 等只支持 HTTP/2 的明文后端
+// 等价于 Versions("h2c", "2")；传输层不配置 tls，与上游之间不使用 TLS
+func (b *ReverseProxyBuilder) H2C() *ReverseProxyBuilder {
+	return b.Versions("h2c", "2")
+}
+
+// GRPC 代理明文 gRPC 后端 - 使用 h2c 并立即刷新响应，保证流式调用不被缓冲
+func (b *ReverseProxyBuilder) GRPC() *ReverseProxyBuilder {
+	return b.H2C().FlushInterval(-1)
+}
+
+// Build 生成 reverse_proxy 处理器
+// 未设置任何上游服务器或构建过程中出现无效参数时返回错误
+func (b *ReverseProxyBuilder) Build() (types.Handler, error) {
+	if b.err != nil {
+		return types.Handler{}, b.err
+	}
+	if len(b.upstreams) == 0 {
+		return types.Handler{}, fmt.Errorf("反向代理至少需要一个上游服务器")
+	}
+
+	handler := types.Handler{
+		Handler:       "reverse_proxy",
+		Upstreams:     append([]types.Upstream(nil), b.upstreams...),
+		FlushInterval: b.flushInterval,
+		LoadBalancing: b.loadBalancing,
+	}
+
+	if b.healthChecks != nil {
+		healthChecks := *b.healthChecks
+		handler.HealthChecks = &healthChecks
+	}
+
+	if b.transport != nil {
+		transport := *b.transport
+		transport.Versions = append([]string(nil), b.transport.Versions...)
+		handler.Transport = &transport
+	}
+
+	if len(b.headerUp) > 0 {
+		set := make(map[string][]string, len(b.headerUp))
+		for name, values := range b.headerUp {
+			set[name] = append([]string(nil), values...)
+		}
+		handler.Headers = &types.HeadersConfig{
+			Request: &types.HeaderOps{Set: set},
+		}
+	}
+
+	return handler, nil
+}
+
+// ensureHealthChecks 返回健康检查配置，必要时创建
+func (b *ReverseProxyBuilder) ensureHealthChecks() *types.HealthChecks {
+	if b.healthChecks == nil {
+		b.healthChecks = &types.HealthChecks{}
+	}
+	return b.healthChecks
+}
+
+// setTimeout 校验并设置传输层超时，必要时创建 http 传输层配置
+func (b *ReverseProxyBuilder) setTimeout(name string, d time.Duration, set func(*types.HTTPTransport)) *ReverseProxyBuilder {
+	if d < 0 {
+		b.setErr(fmt.Errorf("%s超时时间不能为负数", name))
+		return b
+	}
+	set(b.ensureTransport())
+	return b
+}
+
+// ensureTransport 返回 http 传输层配置，必要时创建
+func (b *ReverseProxyBuilder) ensureTransport() *types.HTTPTransport {
+	if b.transport == nil {
+		b.transport = &types.HTTPTransport{Protocol: "http"}
+	}
+	return b.transport
+}
+
+// setErr 记录构建过程中的第一个错误，在 Build 时返回
+func (b *ReverseProxyBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
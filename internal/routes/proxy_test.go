@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestReverseProxyBuilderDurations(t *testing.T) {
+	handler, err := NewReverseProxy().
+		Upstream("localhost:8080").
+		FlushInterval(100*time.Millisecond).
+		LoadBalancing("least_conn", 5*time.Second, 250*time.Millisecond).
+		ActiveHealthCheck("/health", 30*time.Second, 200).
+		PassiveHealthCheck(time.Minute, 3).
+		Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	data, err := json.Marshal(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		FlushInterval string `json:"flush_interval"`
+		LoadBalancing struct {
+			TryDuration string `json:"try_duration"`
+			TryInterval string `json:"try_interval"`
+		} `json:"load_balancing"`
+		HealthChecks struct {
+			Active struct {
+				Interval string `json:"interval"`
+			} `json:"active"`
+			Passive struct {
+				FailDuration string `json:"fail_duration"`
+			} `json:"passive"`
+		} `json:"health_checks"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("时长应序列化为 Caddy 的时长字符串: %v\n%s", err, data)
+	}
+
+	tests := []struct{ field, got, want string }{
+		{"flush_interval", got.FlushInterval, "100ms"},
+		{"try_duration", got.LoadBalancing.TryDuration, "5s"},
+		{"try_interval", got.LoadBalancing.TryInterval, "250ms"},
+		{"active.interval", got.HealthChecks.Active.Interval, "30s"},
+		{"passive.fail_duration", got.HealthChecks.Passive.FailDuration, "1m0s"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, 期望 %q", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestGRPCFlushesImmediately(t *testing.T) {
+	handler, err := NewReverseProxy().Upstream("localhost:50051").GRPC().Build()
+	if err != nil {
+		t.Fatalf("构建失败: %v", err)
+	}
+	data, err := json.Marshal(handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Caddy 将负的 flush_interval 视为每次写入后立即刷新
+	var got struct {
+		FlushInterval string `json:"flush_interval"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("flush_interval 应序列化为时长字符串: %v\n%s", err, data)
+	}
+	if parsed, err := time.ParseDuration(got.FlushInterval); err != nil || parsed >= 0 {
+		t.Errorf("flush_interval = %q, 期望负的时长", got.FlushInterval)
+	}
+}
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// DefaultRateLimitZone 未指定区域名称时使用的限流区域
+const DefaultRateLimitZone = "default"
+
+// RateLimitHandler 生成 rate_limit 处理器
+// rate_limit 不是 Caddy 标准模块，需要使用包含 github.com/mholt/caddy-ratelimit 的 Caddy 构建
+func RateLimitHandler(opts types.RateLimitOptions) (types.Handler, error) {
+	if opts.Events < 1 {
+		return types.Handler{}, fmt.Errorf("限流窗口内的最大请求数必须大于 0")
+	}
+	if opts.Window <= 0 {
+		return types.Handler{}, fmt.Errorf("限流窗口时长必须大于 0")
+	}
+
+	zone := opts.Zone
+	if zone == "" {
+		zone = DefaultRateLimitZone
+	}
+
+	// 默认按客户端 IP 限流，指定请求头时按请求头的值限流
+	key := "{http.request.remote.host}"
+	if opts.Header != "" {
+		key = fmt.Sprintf("{http.request.header.%s}", opts.Header)
+	}
+
+	return types.Handler{
+		Handler: "rate_limit",
+		RateLimits: map[string]types.RateLimitZone{
+			zone: {
+				Key:       key,
+				Window:    types.Duration(opts.Window),
+				MaxEvents: opts.Events,
+			},
+		},
+	}, nil
+}
+
+// EnableRateLimit 为已有路由启用限流
+// 处理器插入到 reverse_proxy/file_server 等处理器之前；路由已有 rate_limit 处理器时不做任何修改
+// Caddy 未安装 rate_limit 模块时返回的 APIError 中 Module 为 "http.handlers.rate_limit"
+func (m *Manager) EnableRateLimit(routeID string, opts types.RateLimitOptions) error {
+	return m.EnableRateLimitContext(context.Background(), routeID, opts)
+}
+
+// EnableRateLimitContext 为已有路由启用限流（支持 context 取消和超时）
+func (m *Manager) EnableRateLimitContext(ctx context.Context, routeID string, opts types.RateLimitOptions) error {
+	handler, err := RateLimitHandler(opts)
+	if err != nil {
+		return err
+	}
+	return m.insertHandler(ctx, routeID, handler)
+}
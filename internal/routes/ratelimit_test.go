@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+func TestMissingModuleReported(t *testing.T) {
+	// 读取请求正常返回，修改请求都返回 Caddy 加载未知模块时的错误
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/routes") {
+				w.Write([]byte(`[]`))
+			} else {
+				w.Write([]byte(`{"handle":[]}`))
+			}
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"loading new config: position 0: loading module 'rate_limit': unknown module: http.handlers.rate_limit"}`))
+	}))
+	defer srv.Close()
+
+	client, err := api.NewClientWithOptions(api.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewManagerWithClient(client)
+
+	limiter, err := RateLimitHandler(types.RateLimitOptions{Events: 10, Window: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	route := types.Route{ID: "api", Handle: []types.Handler{limiter}}
+	config, err := types.NewRouteConfig(route)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"AddRoute", func() error { return m.AddRoute(config) }},
+		{"AddRoutes", func() error { return m.AddRoutes(route) }},
+		{"AddRouteToServer", func() error { return m.AddRouteToServer("srv0", route) }},
+		{"EnableRateLimit", func() error { return m.EnableRateLimit("api", types.RateLimitOptions{Events: 10, Window: time.Minute}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *api.APIError
+			if err := tt.call(); !errors.As(err, &apiErr) || apiErr.Module != "http.handlers.rate_limit" {
+				t.Errorf("err = %v, 期望指出缺少 http.handlers.rate_limit 模块", err)
+			}
+		})
+	}
+}
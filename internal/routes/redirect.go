@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// RedirectRoute 生成重定向路由：static_response 处理器返回重定向状态码和 Location 响应头
+// code 只能是 300、301、302、303、307 或 308，304 等不会跳转的 3xx 状态码会返回错误
+// to 支持 Caddy 占位符 (如 "https://example.com{http.request.uri}")；路由为终端路由，不会继续匹配后续路由
+func RedirectRoute(match types.RouteMatch, to string, code int) (types.Route, error) {
+	if to == "" {
+		return types.Route{}, fmt.Errorf("重定向目标不能为空")
+	}
+	if !isRedirectCode(code) {
+		return types.Route{}, fmt.Errorf("无效的重定向状态码: %d (可选值: 300, 301, 302, 303, 307, 308)", code)
+	}
+
+	return types.Route{
+		Match: []types.RouteMatch{match},
+		Handle: []types.Handler{
+			{
+				Handler:       "static_response",
+				StatusCode:    code,
+				StaticHeaders: map[string][]string{"Location": {to}},
+			},
+		},
+		Terminal: true,
+	}, nil
+}
+
+// AddRedirect 添加重定向路由到默认服务器 (如 www 到主域名、旧路径到新路径)
+// code 为 0 时使用 302 (http.StatusFound)
+func (m *Manager) AddRedirect(match types.RouteMatch, to string, code int) error {
+	return m.AddRedirectContext(context.Background(), match, to, code)
+}
+
+// AddRedirectContext 添加重定向路由（支持 context 取消和超时）
+func (m *Manager) AddRedirectContext(ctx context.Context, match types.RouteMatch, to string, code int) error {
+	if code == 0 {
+		code = http.StatusFound
+	}
+	route, err := RedirectRoute(match, to, code)
+	if err != nil {
+		return err
+	}
+	return m.AddRouteToServerContext(ctx, DefaultServer, route)
+}
+
+// isRedirectCode 判断状态码是否会让客户端按 Location 跳转
+func isRedirectCode(code int) bool {
+	switch code {
+	case http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusFound,
+		http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+func TestRedirectRoute(t *testing.T) {
+	match := types.RouteMatch{Host: []string{"www.example.com"}}
+	tests := []struct {
+		code    int
+		wantErr bool
+	}{
+		{300, false},
+		{301, false},
+		{302, false},
+		{303, false},
+		{307, false},
+		{308, false},
+		{0, true},
+		{200, true},
+		{304, true},
+		{305, true},
+		{306, true},
+		{399, true},
+		{400, true},
+	}
+	for _, tt := range tests {
+		route, err := RedirectRoute(match, "https://example.com{http.request.uri}", tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("RedirectRoute(%d) err = %v, 期望出错: %v", tt.code, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (route.Handle[0].StatusCode != tt.code || !route.Terminal) {
+			t.Errorf("RedirectRoute(%d) = %+v", tt.code, route)
+		}
+	}
+}
+
+func TestAddRedirectDefaultCode(t *testing.T) {
+	m, srv := newTestManager(t, emptyServer)
+	if err := m.AddRedirect(types.RouteMatch{Host: []string{"www.example.com"}}, "https://example.com", 0); err != nil {
+		t.Fatalf("AddRedirect 失败: %v", err)
+	}
+	if got := srv.Get(RoutesPath + "/0/handle/0/status_code"); got != float64(302) {
+		t.Errorf("status_code = %v, 期望 302", got)
+	}
+	if err := m.AddRedirect(types.RouteMatch{Host: []string{"old.example.com"}}, "https://example.com", 304); err == nil {
+		t.Error("304 不应作为重定向状态码")
+	}
+}
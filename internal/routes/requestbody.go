@@ -0,0 +1,114 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// 大小单位 - 与 Caddyfile 的解析规则一致，KB/MB 为 1000 进制，KiB/MiB 为 1024 进制
+var sizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"KB":  1000,
+	"MB":  1000 * 1000,
+	"GB":  1000 * 1000 * 1000,
+	"TB":  1000 * 1000 * 1000 * 1000,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// ParseSize 解析大小字符串 (如 "10MB"、"512KiB"、"1024")，返回字节数
+func ParseSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split < 0 {
+		split = len(trimmed)
+	}
+
+	number, unit := trimmed[:split], strings.ToUpper(strings.TrimSpace(trimmed[split:]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("无效的大小: %q", s)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的大小: %q", s)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// RequestBodyHandler 生成限制请求体大小的 request_body 处理器
+// maxSize 可以是字节数 (int、int64) 或大小字符串 (如 "10MB")，必须大于 0
+func RequestBodyHandler(maxSize interface{}) (types.Handler, error) {
+	var size int64
+	switch v := maxSize.(type) {
+	case int:
+		size = int64(v)
+	case int64:
+		size = v
+	case string:
+		parsed, err := ParseSize(v)
+		if err != nil {
+			return types.Handler{}, err
+		}
+		size = parsed
+	default:
+		return types.Handler{}, fmt.Errorf("不支持的大小类型: %T", maxSize)
+	}
+	if size <= 0 {
+		return types.Handler{}, fmt.Errorf("请求体大小上限必须大于 0")
+	}
+
+	return types.Handler{
+		Handler: "request_body",
+		MaxSize: size,
+	}, nil
+}
+
+// EnableRequestBodyLimit 为已有路由限制请求体大小
+// 处理器插入到 reverse_proxy/file_server 等处理器之前；路由已有 request_body 处理器时只替换其 max_size
+func (m *Manager) EnableRequestBodyLimit(routeID string, maxSize interface{}) error {
+	return m.EnableRequestBodyLimitContext(context.Background(), routeID, maxSize)
+}
+
+// EnableRequestBodyLimitContext 为已有路由限制请求体大小（支持 context 取消和超时）
+func (m *Manager) EnableRequestBodyLimitContext(ctx context.Context, routeID string, maxSize interface{}) error {
+	handler, err := RequestBodyHandler(maxSize)
+	if err != nil {
+		return err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+
+	handlers, _ := route["handle"].([]interface{})
+	for i, h := range handlers {
+		existing, ok := h.(map[string]interface{})
+		if !ok || existing["handler"] != "request_body" {
+			continue
+		}
+		method := "PATCH"
+		if _, ok := existing["max_size"]; !ok {
+			method = "PUT"
+		}
+		return m.client.PutByIDContext(ctx, handler.MaxSize, fmt.Sprintf("%s/handle/%d/max_size", routeID, i), method)
+	}
+
+	return m.insertHandler(ctx, routeID, handler)
+}
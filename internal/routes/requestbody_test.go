@@ -0,0 +1,91 @@
+package routes
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"1024", 1024, false},
+		{"512B", 512, false},
+		{"10KB", 10000, false},
+		{"10kb", 10000, false},
+		{"10MB", 10 * 1000 * 1000, false},
+		{" 10 MB ", 10 * 1000 * 1000, false},
+		{"1GB", 1000 * 1000 * 1000, false},
+		{"2TB", 2 * 1000 * 1000 * 1000 * 1000, false},
+		{"512KiB", 512 << 10, false},
+		{"1MiB", 1 << 20, false},
+		{"1.5MiB", 3 << 19, false},
+		{"1GiB", 1 << 30, false},
+		{"1TiB", 1 << 40, false},
+		{"", 0, true},
+		{"MB", 0, true},
+		{"-1MB", 0, true},
+		{"10XB", 0, true},
+		{"1.2.3KB", 0, true},
+		{"1e3", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSize(%q) err = %v, 期望出错: %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize(%q) = %d, 期望 %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestBodyHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize interface{}
+		want    int64
+		wantErr bool
+	}{
+		{"int", 1024, 1024, false},
+		{"int64", int64(1 << 20), 1 << 20, false},
+		{"string", "10MB", 10 * 1000 * 1000, false},
+		{"zero", 0, 0, true},
+		{"negative", -1, 0, true},
+		{"invalid string", "ten", 0, true},
+		{"unsupported type", 1.5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := RequestBodyHandler(tt.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, 期望出错: %v", err, tt.wantErr)
+			}
+			if err == nil && (handler.Handler != "request_body" || handler.MaxSize != tt.want) {
+				t.Errorf("handler = %+v, 期望 max_size %d", handler, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnableRequestBodyLimitReplacesSize(t *testing.T) {
+	m, srv := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"listen":[":443"],"routes":[
+		{"@id":"upload","handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"localhost:8080"}]}]}
+	]}}}}}`)
+
+	if err := m.EnableRequestBodyLimit("upload", "10MB"); err != nil {
+		t.Fatalf("EnableRequestBodyLimit 失败: %v", err)
+	}
+	if err := m.EnableRequestBodyLimit("upload", 1024); err != nil {
+		t.Fatalf("再次调用 EnableRequestBodyLimit 失败: %v", err)
+	}
+
+	handlers := srv.Get(RoutesPath + "/0/handle").([]interface{})
+	if len(handlers) != 2 {
+		t.Fatalf("处理器 = %v, 期望 request_body 和 reverse_proxy", handlers)
+	}
+	if got := srv.Get(RoutesPath + "/0/handle/0/max_size"); got != float64(1024) {
+		t.Errorf("max_size = %v, 期望 1024", got)
+	}
+}
@@ -0,0 +1,275 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// SetAutomaticHTTPS 设置服务器的自动 HTTPS 选项
+// 只替换 apps/http/servers/<name>/automatic_https 子树；
+// Skip 和 SkipCertificates 中的主机名必须由该服务器的路由提供，否则返回错误
+func (m *Manager) SetAutomaticHTTPS(serverName string, opts types.AutoHTTPSOptions) error {
+	return m.SetAutomaticHTTPSContext(context.Background(), serverName, opts)
+}
+
+// SetAutomaticHTTPSContext 设置服务器的自动 HTTPS 选项（支持 context 取消和超时）
+func (m *Manager) SetAutomaticHTTPSContext(ctx context.Context, serverName string, opts types.AutoHTTPSOptions) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	server, err := m.getServer(ctx, serverName)
+	if err != nil {
+		return err
+	}
+
+	routes, _ := server["routes"].([]interface{})
+	hosts := collectHosts(routes)
+	for _, host := range append(append([]string(nil), opts.Skip...), opts.SkipCertificates...) {
+		if !hostServed(hosts, host) {
+			return fmt.Errorf("服务器 %s 的路由中没有主机名 %s", serverName, host)
+		}
+	}
+
+	return m.configManager.SetConfigPathContext(ctx, ServerPath(serverName)+"/automatic_https", opts)
+}
+
+// 监听地址中允许的网络类型前缀 (如 "tcp6/[::1]:443")
+var listenNetworks = []string{"tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram", "unixpacket"}
+
+// SetListen 设置服务器的监听地址，替换原有的 listen 数组
+// 地址格式为 [network/]host:port[-port]，如 ":443"、"127.0.0.1:8080"、"tcp6/[::1]:8443"
+// 任何地址格式无效时在发送请求前返回错误
+func (m *Manager) SetListen(serverName string, addrs ...string) error {
+	return m.SetListenContext(context.Background(), serverName, addrs...)
+}
+
+// SetListenContext 设置服务器的监听地址（支持 context 取消和超时）
+func (m *Manager) SetListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("服务器至少需要一个监听地址")
+	}
+	for _, addr := range addrs {
+		if err := validateListenAddress(addr); err != nil {
+			return err
+		}
+	}
+	if _, err := m.getServer(ctx, serverName); err != nil {
+		return err
+	}
+	return m.configManager.SetConfigPathContext(ctx, ServerPath(serverName)+"/listen", addrs)
+}
+
+// AddListen 为服务器追加监听地址，已存在的地址会被忽略
+func (m *Manager) AddListen(serverName string, addrs ...string) error {
+	return m.AddListenContext(context.Background(), serverName, addrs...)
+}
+
+// AddListenContext 为服务器追加监听地址（支持 context 取消和超时）
+func (m *Manager) AddListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, addr := range addrs {
+		if err := validateListenAddress(addr); err != nil {
+			return err
+		}
+	}
+
+	listen, err := m.getListen(ctx, serverName)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, addr := range addrs {
+		if !utils.StringSliceContains(listen, addr) {
+			listen = append(listen, addr)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return m.configManager.SetConfigPathContext(ctx, ServerPath(serverName)+"/listen", listen)
+}
+
+// RemoveListen 从服务器删除监听地址，不存在的地址会被忽略
+// 删除后服务器没有任何监听地址时返回错误
+func (m *Manager) RemoveListen(serverName string, addrs ...string) error {
+	return m.RemoveListenContext(context.Background(), serverName, addrs...)
+}
+
+// RemoveListenContext 从服务器删除监听地址（支持 context 取消和超时）
+func (m *Manager) RemoveListenContext(ctx context.Context, serverName string, addrs ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	listen, err := m.getListen(ctx, serverName)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]string, 0, len(listen))
+	for _, addr := range listen {
+		if !utils.StringSliceContains(addrs, addr) {
+			remaining = append(remaining, addr)
+		}
+	}
+	if len(remaining) == len(listen) {
+		return nil
+	}
+	if len(remaining) == 0 {
+		return fmt.Errorf("服务器 %s 至少需要保留一个监听地址", serverName)
+	}
+	return m.configManager.SetConfigPathContext(ctx, ServerPath(serverName)+"/listen", remaining)
+}
+
+// getListen 获取服务器当前的监听地址
+func (m *Manager) getListen(ctx context.Context, serverName string) ([]string, error) {
+	server, err := m.getServer(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := server["listen"].([]interface{})
+	listen := make([]string, 0, len(values))
+	for _, v := range values {
+		if addr, ok := v.(string); ok {
+			listen = append(listen, addr)
+		}
+	}
+	return listen, nil
+}
+
+// validateListenAddress 校验 Caddy 网络地址格式 [network/]host:port[-port]
+func validateListenAddress(addr string) error {
+	address := addr
+	if network, rest, found := strings.Cut(addr, "/"); found && utils.StringSliceContains(listenNetworks, network) {
+		if strings.HasPrefix(network, "unix") {
+			if rest == "" {
+				return fmt.Errorf("无效的监听地址 %q: 缺少套接字路径", addr)
+			}
+			return nil
+		}
+		address = rest
+	}
+
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("无效的监听地址 %q: %w", addr, err)
+	}
+
+	start, end, isRange := strings.Cut(port, "-")
+	first, err := parsePort(start)
+	if err != nil {
+		return fmt.Errorf("无效的监听地址 %q: %w", addr, err)
+	}
+	if isRange {
+		last, err := parsePort(end)
+		if err != nil {
+			return fmt.Errorf("无效的监听地址 %q: %w", addr, err)
+		}
+		if last < first {
+			return fmt.Errorf("无效的监听地址 %q: 端口范围的结束端口小于起始端口", addr)
+		}
+	}
+	return nil
+}
+
+// parsePort 解析端口号，范围为 0-65535
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, fmt.Errorf("无效的端口: %q", s)
+	}
+	return port, nil
+}
+
+// RouteHosts 返回路由及其子路由的匹配条件中出现的所有主机名
+func (m *Manager) RouteHosts(ctx context.Context, routeID string) ([]string, error) {
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return nil, fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+	return collectHosts([]interface{}{route}), nil
+}
+
+// walkRoutes 遍历路由列表，包括 subroute 处理器中嵌套的路由
+func walkRoutes(routes []interface{}, fn func(route map[string]interface{})) {
+	for _, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fn(route)
+
+		handlers, _ := route["handle"].([]interface{})
+		for _, h := range handlers {
+			handler, ok := h.(map[string]interface{})
+			if !ok || handler["handler"] != "subroute" {
+				continue
+			}
+			nested, _ := handler["routes"].([]interface{})
+			walkRoutes(nested, fn)
+		}
+	}
+}
+
+// collectHosts 收集路由匹配条件中出现的所有主机名
+func collectHosts(routes []interface{}) []string {
+	var hosts []string
+	walkRoutes(routes, func(route map[string]interface{}) {
+		sets, _ := route["match"].([]interface{})
+		for _, s := range sets {
+			set, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			values, _ := set["host"].([]interface{})
+			for _, v := range values {
+				if host, ok := v.(string); ok {
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	})
+	return hosts
+}
+
+// hostServed 判断主机名是否被路由中的某个主机名覆盖，支持单级通配符 (如 "*.example.com")
+func hostServed(hosts []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range hosts {
+		pattern = strings.ToLower(pattern)
+		if pattern == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if label, rest, found := strings.Cut(host, "."); found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
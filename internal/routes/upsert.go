@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// UpsertRoute 添加或替换指定 @id 的路由，调用后该路由的配置与传入值完全一致
+// 路由已存在时通过 /id/<id> 原位替换（Caddy 中替换已有值使用 PATCH，PUT 会在数组中插入新元素），
+// 否则追加到默认服务器；可重复调用，返回值表示本次是新建 (true) 还是更新 (false)
+func (m *Manager) UpsertRoute(id string, route types.RouteDefinition) (bool, error) {
+	return m.UpsertRouteContext(context.Background(), id, route)
+}
+
+// UpsertRouteContext 添加或替换指定 @id 的路由（支持 context 取消和超时）
+func (m *Manager) UpsertRouteContext(ctx context.Context, id string, route types.RouteDefinition) (bool, error) {
+	config, err := route.ToRouteConfig()
+	if err != nil {
+		return false, err
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if id == "" {
+		return false, fmt.Errorf("路由 ID 不能为空")
+	}
+	if config.ID != "" && config.ID != id {
+		return false, fmt.Errorf("路由的 @id (%s) 与指定的 ID (%s) 不一致", config.ID, id)
+	}
+	// 替换后仍需能通过同一 ID 找到该路由
+	config.ID = id
+
+	exists, err := m.RouteExistsContext(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		if err := m.client.PutByIDContext(ctx, config, id, "PATCH"); err != nil {
+			return false, fmt.Errorf("更新路由 %s 失败: %w", id, err)
+		}
+		return false, nil
+	}
+
+	if err := m.AddRouteToServerContext(ctx, DefaultServer, config); err != nil {
+		return false, fmt.Errorf("添加路由 %s 失败: %w", id, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// UpstreamsPath 管理 API 中查询上游服务器实时状态的端点
+const UpstreamsPath = "/reverse_proxy/upstreams"
+
+// AddUpstream 向已有的反向代理路由追加上游服务器
+// 通过 /id/<routeID>/handle/<n>/upstreams 只追加单个元素，不重写整个配置
+// 上游已存在时直接返回，不会产生重复项
+func (m *Manager) AddUpstream(routeID, dial string) error {
+	return m.AddUpstreamContext(context.Background(), routeID, dial)
+}
+
+// AddUpstreamContext 向已有的反向代理路由追加上游服务器（支持 context 取消和超时）
+func (m *Manager) AddUpstreamContext(ctx context.Context, routeID, dial string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if dial == "" {
+		return fmt.Errorf("上游地址不能为空")
+	}
+
+	index, handler, err := m.findReverseProxy(ctx, routeID)
+	if err != nil {
+		return err
+	}
+
+	upstreams, isArray := handler["upstreams"].([]interface{})
+	// 已存在相同上游，保持幂等
+	if upstreamIndex(upstreams, dial) >= 0 {
+		return nil
+	}
+
+	upstreamsPath := fmt.Sprintf("%s/handle/%d/upstreams", routeID, index)
+	upstream := types.Upstream{Dial: dial}
+	if !isArray {
+		// 键不存在时 POST 会把键设置为单个对象而不是数组，因此整体设置为只包含该上游的数组
+		method := "PUT"
+		if _, exists := handler["upstreams"]; exists {
+			method = "PATCH"
+		}
+		return m.client.PutByIDContext(ctx, []types.Upstream{upstream}, upstreamsPath, method)
+	}
+	// 对已有数组路径使用 POST 即为追加单个元素
+	return m.client.PutByIDContext(ctx, upstream, upstreamsPath, "POST")
+}
+
+// RemoveUpstream 从已有的反向代理路由中删除上游服务器
+// 上游不存在时直接返回，便于重复调用
+func (m *Manager) RemoveUpstream(routeID, dial string) error {
+	return m.RemoveUpstreamContext(context.Background(), routeID, dial)
+}
+
+// RemoveUpstreamContext 从已有的反向代理路由中删除上游服务器（支持 context 取消和超时）
+func (m *Manager) RemoveUpstreamContext(ctx context.Context, routeID, dial string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, handler, err := m.findReverseProxy(ctx, routeID)
+	if err != nil {
+		return err
+	}
+
+	upstreams, _ := handler["upstreams"].([]interface{})
+	position := upstreamIndex(upstreams, dial)
+	if position < 0 {
+		return nil
+	}
+
+	upstreamPath := fmt.Sprintf("%s/handle/%d/upstreams/%d", routeID, index, position)
+	return m.client.DeleteByIDContext(ctx, upstreamPath)
+}
+
+// GetUpstreams 查询所有反向代理上游服务器的实时状态
+// 可用于监控或在蓝绿切换前确认新上游已健康；reverse_proxy 模块未加载时返回明确的错误
+func (m *Manager) GetUpstreams(ctx context.Context) ([]types.UpstreamStatus, error) {
+	data, err := m.client.GetEndpoint(ctx, UpstreamsPath)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return nil, fmt.Errorf("Caddy 未加载 reverse_proxy 模块: %w", err)
+		}
+		return nil, err
+	}
+
+	var upstreams []types.UpstreamStatus
+	if err := json.Unmarshal(data, &upstreams); err != nil {
+		return nil, fmt.Errorf("解析上游状态失败: %w", err)
+	}
+	for i := range upstreams {
+		upstreams[i].Healthy = upstreams[i].Fails == 0
+	}
+	return upstreams, nil
+}
+
+// findReverseProxy 查找路由中的 reverse_proxy 处理器
+// 返回处理器在 handle 数组中的索引及其配置
+func (m *Manager) findReverseProxy(ctx context.Context, routeID string) (int, map[string]interface{}, error) {
+	route, err := m.client.GetByIDContext(ctx, routeID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("获取路由 %s 失败: %w", routeID, err)
+	}
+
+	handlers, _ := route["handle"].([]interface{})
+	for i, h := range handlers {
+		handler, ok := h.(map[string]interface{})
+		if !ok || handler["handler"] != "reverse_proxy" {
+			continue
+		}
+		return i, handler, nil
+	}
+
+	return 0, nil, fmt.Errorf("路由 %s 不是 reverse_proxy 处理器", routeID)
+}
+
+// upstreamIndex 返回指定地址在上游列表中的位置，不存在时返回 -1
+func upstreamIndex(upstreams []interface{}, dial string) int {
+	for i, u := range upstreams {
+		if upstream, ok := u.(map[string]interface{}); ok && upstream["dial"] == dial {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddUpstream(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  string
+		dials    []string
+		wantDial []interface{}
+	}{
+		{"no upstreams key", `{"handler":"reverse_proxy"}`, []string{"a:80"}, []interface{}{"a:80"}},
+		{"no upstreams key then append", `{"handler":"reverse_proxy"}`, []string{"a:80", "b:80"}, []interface{}{"a:80", "b:80"}},
+		{"null upstreams", `{"handler":"reverse_proxy","upstreams":null}`, []string{"a:80"}, []interface{}{"a:80"}},
+		{"empty upstreams", `{"handler":"reverse_proxy","upstreams":[]}`, []string{"a:80"}, []interface{}{"a:80"}},
+		{"existing upstreams", `{"handler":"reverse_proxy","upstreams":[{"dial":"a:80"}]}`, []string{"b:80"}, []interface{}{"a:80", "b:80"}},
+		{"duplicate", `{"handler":"reverse_proxy","upstreams":[{"dial":"a:80"}]}`, []string{"a:80", "a:80"}, []interface{}{"a:80"}},
+		{"after other handler", `{"handler":"headers"},{"handler":"reverse_proxy"}`, []string{"a:80"}, []interface{}{"a:80"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, srv := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"routes":[{"@id":"app","handle":[`+tt.handler+`]}]}}}}}`)
+			for _, dial := range tt.dials {
+				if err := m.AddUpstream("app", dial); err != nil {
+					t.Fatalf("AddUpstream(%s) 失败: %v", dial, err)
+				}
+			}
+
+			handle := srv.Get(RoutesPath + "/0/handle").([]interface{})
+			proxy := handle[len(handle)-1].(map[string]interface{})
+			upstreams, ok := proxy["upstreams"].([]interface{})
+			if !ok {
+				t.Fatalf("upstreams 不是数组: %#v", proxy["upstreams"])
+			}
+			var dials []interface{}
+			for _, u := range upstreams {
+				dials = append(dials, u.(map[string]interface{})["dial"])
+			}
+			if !reflect.DeepEqual(dials, tt.wantDial) {
+				t.Errorf("上游 = %v, 期望 %v", dials, tt.wantDial)
+			}
+		})
+	}
+}
+
+func TestRemoveUpstream(t *testing.T) {
+	m, srv := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"routes":[{"@id":"app","handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"a:80"},{"dial":"b:80"}]}]}]}}}}}`)
+
+	if err := m.RemoveUpstream("app", "a:80"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RemoveUpstream("app", "missing:80"); err != nil {
+		t.Fatalf("删除不存在的上游应直接返回: %v", err)
+	}
+	upstreams := srv.Get(RoutesPath + "/0/handle/0/upstreams").([]interface{})
+	if len(upstreams) != 1 || upstreams[0].(map[string]interface{})["dial"] != "b:80" {
+		t.Errorf("upstreams = %v", upstreams)
+	}
+}
+
+func TestAddUpstreamNotReverseProxy(t *testing.T) {
+	m, _ := newTestManager(t, `{"apps":{"http":{"servers":{"srv0":{"routes":[{"@id":"app","handle":[{"handler":"file_server"}]}]}}}}}`)
+	if err := m.AddUpstream("app", "a:80"); err == nil {
+		t.Error("路由没有 reverse_proxy 处理器时应返回错误")
+	}
+}
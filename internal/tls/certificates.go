@@ -0,0 +1,106 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	cryptotls "crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/utils"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// 常量定义 - 手动加载证书的配置路径
+const (
+	CertificatesPath = "/apps/tls/certificates"
+	LoadPEMPath      = CertificatesPath + "/load_pem"
+)
+
+// LoadPEMCertificate 将 PEM 格式的证书和私钥加载到 Caddy 的 tls 应用
+// 适用于自有 CA 签发的内部证书；tags 可供路由的连接策略按标签引用该证书。
+// 发送前会校验私钥与证书是否匹配；相同证书已加载时只为其补充缺少的标签
+func (m *Manager) LoadPEMCertificate(certPEM, keyPEM []byte, tags ...string) error {
+	return m.LoadPEMCertificateContext(context.Background(), certPEM, keyPEM, tags...)
+}
+
+// LoadPEMCertificateContext 将 PEM 格式的证书和私钥加载到 Caddy（支持 context 取消和超时）
+func (m *Manager) LoadPEMCertificateContext(ctx context.Context, certPEM, keyPEM []byte, tags ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// X509KeyPair 会校验私钥与证书的公钥是否匹配
+	pair, err := cryptotls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("证书与私钥无效或不匹配: %w", err)
+	}
+	leaf := pair.Certificate[0]
+
+	// tls 应用尚未配置证书时，Caddy 对中间层级不存在的路径返回 400，视为没有已加载的证书
+	data, err := m.configManager.GetConfigPathContext(ctx, LoadPEMPath)
+	if api.IsNotFound(err) {
+		data = nil
+	} else if err != nil {
+		return err
+	}
+
+	var loaded []types.PEMCertificate
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return fmt.Errorf("解析已加载的证书失败: %w", err)
+		}
+	}
+	for i, cert := range loaded {
+		if bytes.Equal(firstCertificateDER([]byte(cert.Certificate)), leaf) {
+			return m.mergeCertificateTags(ctx, i, cert.Tags, tags)
+		}
+	}
+
+	entry := types.PEMCertificate{
+		Certificate: string(certPEM),
+		Key:         string(keyPEM),
+		Tags:        tags,
+	}
+
+	// load_pem 数组不存在时先创建所在的对象路径，再设置为只包含该证书的数组
+	if loaded == nil {
+		if err := m.configManager.EnsurePathContext(ctx, CertificatesPath); err != nil {
+			return err
+		}
+		return m.configManager.SetConfigPathContext(ctx, LoadPEMPath, []types.PEMCertificate{entry})
+	}
+	return m.configManager.AppendConfigPathContext(ctx, LoadPEMPath, entry)
+}
+
+// mergeCertificateTags 为已加载的证书补充缺少的标签，标签都已存在时不做修改
+func (m *Manager) mergeCertificateTags(ctx context.Context, index int, existing, tags []string) error {
+	merged := append([]string(nil), existing...)
+	for _, tag := range tags {
+		if !utils.StringSliceContains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	if len(merged) == len(existing) {
+		return nil // 相同证书及标签已加载
+	}
+	return m.configManager.SetConfigPathContext(ctx, fmt.Sprintf("%s/%d/tags", LoadPEMPath, index), merged)
+}
+
+// firstCertificateDER 返回 PEM 数据中第一个证书的 DER 编码，用于比较证书是否相同
+func firstCertificateDER(certPEM []byte) []byte {
+	for {
+		var block *pem.Block
+		block, certPEM = pem.Decode(certPEM)
+		if block == nil {
+			return nil
+		}
+		if block.Type == "CERTIFICATE" {
+			return block.Bytes
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/internal/caddytest"
+)
+
+// newTestManager 创建连接到模拟服务器的 TLS 管理器
+func newTestManager(t *testing.T, initial string) (*Manager, *caddytest.Server) {
+	t.Helper()
+	srv := caddytest.NewServer(t, initial)
+	client, err := api.NewClientWithOptions(api.Options{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	return NewManagerWithClient(client), srv
+}
+
+// newKeyPair 生成自签名证书和私钥的 PEM
+func newKeyPair(t *testing.T, name string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestLoadPEMCertificate(t *testing.T) {
+	certA, keyA := newKeyPair(t, "a.internal")
+	certB, keyB := newKeyPair(t, "b.internal")
+
+	tests := []struct {
+		name    string
+		initial string
+	}{
+		{"no config", ""},
+		{"no tls app", `{"apps":{"http":{}}}`},
+		{"empty tls app", `{"apps":{"tls":{}}}`},
+		{"empty load_pem", `{"apps":{"tls":{"certificates":{"load_pem":[]}}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, srv := newTestManager(t, tt.initial)
+
+			if err := m.LoadPEMCertificate(certA, keyA, "internal"); err != nil {
+				t.Fatalf("加载第一个证书失败: %v", err)
+			}
+			if err := m.LoadPEMCertificate(certB, keyB); err != nil {
+				t.Fatalf("加载第二个证书失败: %v", err)
+			}
+
+			loaded, ok := srv.Get(LoadPEMPath).([]interface{})
+			if !ok || len(loaded) != 2 {
+				t.Fatalf("load_pem = %v, 期望包含 2 个证书", srv.Get(LoadPEMPath))
+			}
+		})
+	}
+}
+
+func TestLoadPEMCertificateMergesTags(t *testing.T) {
+	cert, key := newKeyPair(t, "a.internal")
+	m, srv := newTestManager(t, "")
+
+	steps := []struct {
+		tags []string
+		want []interface{}
+	}{
+		{[]string{"internal"}, []interface{}{"internal"}},
+		{[]string{"internal"}, []interface{}{"internal"}},
+		{[]string{"mtls", "internal"}, []interface{}{"internal", "mtls"}},
+		{nil, []interface{}{"internal", "mtls"}},
+	}
+	for i, step := range steps {
+		if err := m.LoadPEMCertificate(cert, key, step.tags...); err != nil {
+			t.Fatalf("第 %d 次加载失败: %v", i+1, err)
+		}
+		if got := srv.Get(LoadPEMPath + "/0/tags"); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("第 %d 次加载后 tags = %v, 期望 %v", i+1, got, step.want)
+		}
+	}
+	if loaded := srv.Get(LoadPEMPath).([]interface{}); len(loaded) != 1 {
+		t.Errorf("相同证书被重复加载: %d 个", len(loaded))
+	}
+}
+
+func TestLoadPEMCertificateWithoutTagsThenTagged(t *testing.T) {
+	cert, key := newKeyPair(t, "a.internal")
+	m, srv := newTestManager(t, "")
+
+	if err := m.LoadPEMCertificate(cert, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.LoadPEMCertificate(cert, key, "internal"); err != nil {
+		t.Fatalf("为已加载证书添加标签失败: %v", err)
+	}
+	if got := srv.Get(LoadPEMPath + "/0/tags"); !reflect.DeepEqual(got, []interface{}{"internal"}) {
+		t.Errorf("tags = %v", got)
+	}
+}
+
+func TestLoadPEMCertificateMismatchedKey(t *testing.T) {
+	cert, _ := newKeyPair(t, "a.internal")
+	_, otherKey := newKeyPair(t, "b.internal")
+	m, srv := newTestManager(t, "")
+
+	if err := m.LoadPEMCertificate(cert, otherKey); err == nil {
+		t.Fatal("私钥与证书不匹配时应返回错误")
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("校验失败时不应发送请求: %v", srv.Requests())
+	}
+}
@@ -0,0 +1,131 @@
+package tls
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// PoliciesPath TLS 自动化策略列表的配置路径
+const PoliciesPath = AutomationPath + "/policies"
+
+// ACMEIssuer 生成 acme 证书颁发者
+// ca 为 ACME 目录 URL (为空时使用 Let's Encrypt)；eab 为 CA 要求的外部账号绑定凭据，可为空
+func ACMEIssuer(ca, email string, eab *types.ExternalAccount) (types.TLSIssuer, error) {
+	if ca != "" {
+		u, err := url.Parse(ca)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return types.TLSIssuer{}, fmt.Errorf("无效的 ACME 目录 URL: %s", ca)
+		}
+	}
+	if eab != nil && (eab.KeyID == "" || eab.MACKey == "") {
+		return types.TLSIssuer{}, fmt.Errorf("外部账号绑定需要同时提供 key_id 和 mac_key")
+	}
+
+	issuer := types.TLSIssuer{
+		Module: "acme",
+		CA:     ca,
+		Email:  email,
+	}
+	if eab != nil {
+		account := *eab
+		issuer.ExternalAccount = &account
+	}
+	return issuer, nil
+}
+
+// AddIssuerPolicy 为指定主机名设置证书颁发者，其他主机名保持使用默认颁发者
+// 策略插入到第一个不限主机名的策略之前，以免被其提前匹配；
+// 主机名完全相同的策略已存在时只替换其颁发者，on_demand、key_type 等其他字段保持不变
+func (m *Manager) AddIssuerPolicy(subjects []string, issuers ...types.TLSIssuer) error {
+	return m.AddIssuerPolicyContext(context.Background(), subjects, issuers...)
+}
+
+// AddIssuerPolicyContext 为指定主机名设置证书颁发者（支持 context 取消和超时）
+func (m *Manager) AddIssuerPolicyContext(ctx context.Context, subjects []string, issuers ...types.TLSIssuer) error {
+	if len(subjects) == 0 {
+		return fmt.Errorf("至少需要指定一个主机名")
+	}
+	if len(issuers) == 0 {
+		return fmt.Errorf("至少需要指定一个证书颁发者")
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	policies, err := m.getPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for i, existing := range policies {
+		if sameSubjects(existing.Subjects, subjects) {
+			return m.configManager.SetConfigPathContext(ctx, fmt.Sprintf("%s/%d/issuers", PoliciesPath, i), issuers)
+		}
+	}
+	return m.insertPolicy(ctx, policies, types.TLSAutomationPolicy{
+		Subjects: append([]string(nil), subjects...),
+		Issuers:  issuers,
+	})
+}
+
+// insertPolicy 添加自动化策略
+// 限定主机名的策略插入到第一个不限主机名的策略之前，以免被其提前匹配；不限主机名的策略追加到末尾
+func (m *Manager) insertPolicy(ctx context.Context, policies []types.TLSAutomationPolicy, policy types.TLSAutomationPolicy) error {
+	if policies == nil {
+		if err := m.configManager.EnsurePathContext(ctx, AutomationPath); err != nil {
+			return err
+		}
+		return m.configManager.SetConfigPathContext(ctx, PoliciesPath, []types.TLSAutomationPolicy{policy})
+	}
+
+	if len(policy.Subjects) > 0 {
+		for i, existing := range policies {
+			// 对数组元素路径使用 PUT 会在该位置插入
+			if len(existing.Subjects) == 0 {
+				return m.client.PutConfigContext(ctx, policy, fmt.Sprintf("%s/%d", PoliciesPath, i), "PUT")
+			}
+		}
+	}
+	return m.configManager.AppendConfigPathContext(ctx, PoliciesPath, policy)
+}
+
+// getPolicies 获取现有的自动化策略，tls 应用尚未配置时返回 nil
+func (m *Manager) getPolicies(ctx context.Context) ([]types.TLSAutomationPolicy, error) {
+	data, err := m.configManager.GetConfigPathContext(ctx, PoliciesPath)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var policies []types.TLSAutomationPolicy
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("解析 TLS 自动化策略失败: %w", err)
+	}
+	return policies, nil
+}
+
+// sameSubjects 判断两组主机名是否相同，与顺序无关
+func sameSubjects(a, b []string) bool {
+	if len(a) != len(b) || len(a) == 0 {
+		return false
+	}
+	x, y := append([]string(nil), a...), append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package tls
+
+import "testing"
+
+func TestAddIssuerPolicyKeepsPolicyFields(t *testing.T) {
+	m, srv := newTestManager(t, `{"apps":{"tls":{"automation":{"policies":[
+		{"subjects":["shop.example.com"],"key_type":"p256","must_staple":true},
+		{}]}}}}`)
+
+	if err := m.EnableOnDemandForHosts("shop.example.com"); err != nil {
+		t.Fatalf("开启按需 TLS 失败: %v", err)
+	}
+
+	first, err := ACMEIssuer("https://acme.internal/directory", "ops@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ACMEIssuer("https://acme-v02.api.letsencrypt.org/directory", "ops@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddIssuerPolicy([]string{"shop.example.com"}, first); err != nil {
+		t.Fatalf("设置颁发者失败: %v", err)
+	}
+	if err := m.AddIssuerPolicy([]string{"shop.example.com"}, second); err != nil {
+		t.Fatalf("替换颁发者失败: %v", err)
+	}
+
+	policies := srv.Get(PoliciesPath).([]interface{})
+	if len(policies) != 2 {
+		t.Fatalf("策略数量 = %d, 期望仍为 2: %v", len(policies), policies)
+	}
+	policy := policies[0].(map[string]interface{})
+	for key, want := range map[string]interface{}{"on_demand": true, "key_type": "p256", "must_staple": true} {
+		if policy[key] != want {
+			t.Errorf("%s = %v, 期望 %v (策略的其他字段不应被覆盖)", key, policy[key], want)
+		}
+	}
+	issuers := policy["issuers"].([]interface{})
+	if len(issuers) != 1 || issuers[0].(map[string]interface{})["ca"] != "https://acme-v02.api.letsencrypt.org/directory" {
+		t.Errorf("issuers = %v, 期望被替换为第二个颁发者", issuers)
+	}
+}
+
+func TestAddIssuerPolicyInsertsBeforeCatchAll(t *testing.T) {
+	m, srv := newTestManager(t, "")
+	issuer, err := ACMEIssuer("https://acme.internal/directory", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.EnableOnDemandForHosts(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddIssuerPolicy([]string{"a.example.com"}, issuer); err != nil {
+		t.Fatalf("设置颁发者失败: %v", err)
+	}
+
+	policies := srv.Get(PoliciesPath).([]interface{})
+	if len(policies) != 2 || policies[0].(map[string]interface{})["subjects"] == nil {
+		t.Errorf("限定主机名的策略应在不限主机名的策略之前: %v", policies)
+	}
+}
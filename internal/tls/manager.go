@@ -1,6 +1,8 @@
 package tls
 
 import (
+	"context"
+
 	"github.com/OrbitDeploy/fastcaddy/internal/api"
 	"github.com/OrbitDeploy/fastcaddy/internal/config"
 	"github.com/OrbitDeploy/fastcaddy/pkg/types"
@@ -17,9 +19,15 @@ type Manager struct {
 
 // NewManager 创建新的 TLS 管理器
 func NewManager() *Manager {
+	return NewManagerWithClient(api.NewClient())
+}
+
+// NewManagerWithClient 使用指定的 API 客户端创建 TLS 管理器
+// 所有子管理器共享同一个客户端，从而共享连接方式和传输层
+func NewManagerWithClient(client *api.Client) *Manager {
 	return &Manager{
-		client:        api.NewClient(),
-		configManager: config.NewManager(),
+		client:        client,
+		configManager: config.NewManagerWithClient(client),
 	}
 }
 
@@ -46,18 +54,29 @@ func GetACMEConfig(token string) map[string]interface{} {
 // AddTLSInternalConfig 添加内部 TLS 配置 - 对应 Python 的 add_tls_internal_config() 函数
 // 为本地开发环境配置内部证书颁发者
 func (m *Manager) AddTLSInternalConfig() error {
+	return m.AddTLSInternalConfigContext(context.Background())
+}
+
+// AddTLSInternalConfigContext 添加内部 TLS 配置（支持 context 取消和超时）
+func (m *Manager) AddTLSInternalConfigContext(ctx context.Context) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 检查自动化路径是否已存在
-	if m.client.HasPath(AutomationPath) {
+	if m.client.HasPathContext(ctx, AutomationPath) {
 		return nil // 已存在，无需重复配置
 	}
 
 	// 创建空的根配置
-	if err := m.client.PutConfig(map[string]interface{}{}, "/", "POST"); err != nil {
+	if err := m.client.PutConfigContext(ctx, map[string]interface{}{}, "/", "POST"); err != nil {
 		return err
 	}
 
 	// 初始化自动化路径
-	if err := m.configManager.InitPath(AutomationPath, 0); err != nil {
+	if err := m.configManager.InitPathContext(ctx, AutomationPath, 0); err != nil {
 		return err
 	}
 
@@ -74,24 +93,35 @@ func (m *Manager) AddTLSInternalConfig() error {
 
 	// 设置策略配置
 	policiesPath := AutomationPath + "/policies"
-	return m.client.PutConfig(policies, policiesPath, "POST")
+	return m.client.PutConfigContext(ctx, policies, policiesPath, "POST")
 }
 
 // AddACMEConfig 添加 ACME 配置 - 对应 Python 的 add_acme_config(cf_token) 函数
 // 为生产环境配置 ACME 证书颁发者（使用 Cloudflare）
 func (m *Manager) AddACMEConfig(cfToken string) error {
+	return m.AddACMEConfigContext(context.Background(), cfToken)
+}
+
+// AddACMEConfigContext 添加 ACME 配置（支持 context 取消和超时）
+func (m *Manager) AddACMEConfigContext(ctx context.Context, cfToken string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 检查自动化路径是否已存在
-	if m.client.HasPath(AutomationPath) {
+	if m.client.HasPathContext(ctx, AutomationPath) {
 		return nil // 已存在，无需重复配置
 	}
 
 	// 创建空的根配置
-	if err := m.client.PutConfig(map[string]interface{}{}, "/", "POST"); err != nil {
+	if err := m.client.PutConfigContext(ctx, map[string]interface{}{}, "/", "POST"); err != nil {
 		return err
 	}
 
 	// 初始化自动化路径
-	if err := m.configManager.InitPath(AutomationPath, 0); err != nil {
+	if err := m.configManager.InitPathContext(ctx, AutomationPath, 0); err != nil {
 		return err
 	}
 
@@ -108,12 +138,23 @@ func (m *Manager) AddACMEConfig(cfToken string) error {
 
 	// 设置策略配置
 	policiesPath := AutomationPath + "/policies"
-	return m.client.PutConfig(policies, policiesPath, "POST")
+	return m.client.PutConfigContext(ctx, policies, policiesPath, "POST")
 }
 
 // SetupPKITrust 配置 PKI 证书颁发机构信任 - 对应 Python 的 setup_pki_trust(install_trust) 函数
 // 设置是否将内部 CA 证书安装到系统信任存储
 func (m *Manager) SetupPKITrust(installTrust *bool) error {
+	return m.SetupPKITrustContext(context.Background(), installTrust)
+}
+
+// SetupPKITrustContext 配置 PKI 证书颁发机构信任（支持 context 取消和超时）
+func (m *Manager) SetupPKITrustContext(ctx context.Context, installTrust *bool) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// 如果 installTrust 为 nil，不进行任何操作
 	if installTrust == nil {
 		return nil
@@ -123,7 +164,7 @@ func (m *Manager) SetupPKITrust(installTrust *bool) error {
 	pkiPath := "/apps/pki/certificate_authorities/local"
 
 	// 初始化 PKI 路径，跳过第一级 (apps)
-	if err := m.configManager.InitPath(pkiPath, 1); err != nil {
+	if err := m.configManager.InitPathContext(ctx, pkiPath, 1); err != nil {
 		return err
 	}
 
@@ -133,5 +174,5 @@ func (m *Manager) SetupPKITrust(installTrust *bool) error {
 	}
 
 	// 设置 PKI 配置
-	return m.client.PutConfig(pkiConfig, pkiPath, "POST")
+	return m.client.PutConfigContext(ctx, pkiConfig, pkiPath, "POST")
 }
@@ -0,0 +1,115 @@
+package tls
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+// OnDemandPath 按需 TLS 配置路径
+const OnDemandPath = AutomationPath + "/on_demand"
+
+// EnableOnDemandTLS 配置按需 TLS 的授权端点和频率限制
+// Caddy 为未知主机名申请证书前会请求 askURL?domain=<主机名>，只有返回 2xx 时才申请；
+// rateLimit 为零值时不限制。只有开启了按需 TLS 的策略（见 EnableOnDemandForHosts）才会使用该配置
+func (m *Manager) EnableOnDemandTLS(askURL string, rateLimit types.OnDemandRateLimit) error {
+	return m.EnableOnDemandTLSContext(context.Background(), askURL, rateLimit)
+}
+
+// EnableOnDemandTLSContext 配置按需 TLS 的授权端点和频率限制（支持 context 取消和超时）
+func (m *Manager) EnableOnDemandTLSContext(ctx context.Context, askURL string, rateLimit types.OnDemandRateLimit) error {
+	if _, err := parseAskURL(askURL); err != nil {
+		return err
+	}
+	if rateLimit.Interval < 0 || rateLimit.Burst < 0 {
+		return fmt.Errorf("按需 TLS 频率限制不能为负数")
+	}
+	if (rateLimit.Interval > 0) != (rateLimit.Burst > 0) {
+		return fmt.Errorf("按需 TLS 频率限制需要同时设置 interval 和 burst")
+	}
+
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	onDemand := types.OnDemandConfig{
+		Permission: &types.OnDemandPermission{Module: "http", Endpoint: askURL},
+	}
+	if rateLimit.Burst > 0 {
+		onDemand.RateLimit = &rateLimit
+	}
+
+	if err := m.configManager.EnsurePathContext(ctx, AutomationPath); err != nil {
+		return err
+	}
+	return m.configManager.SetConfigPathContext(ctx, OnDemandPath, onDemand)
+}
+
+// CheckAskEndpoint 检查 Caddy 所在主机能否连接到授权端点
+// 连接从 Caddy 的网络视角建立（配置了 SSH 时在远端发起），只检查 TCP 连通性，不发送请求
+func (m *Manager) CheckAskEndpoint(ctx context.Context, askURL string) error {
+	u, err := parseAskURL(askURL)
+	if err != nil {
+		return err
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	conn, err := m.client.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return fmt.Errorf("无法连接按需 TLS 授权端点 %s: %w", askURL, err)
+	}
+	return conn.Close()
+}
+
+// EnableOnDemandForHosts 允许为指定主机名按需申请证书
+// 主机名已有策略时只在该策略上开启 on_demand，保留其颁发者；
+// hosts 为空时对不限主机名的策略开启，适用于事先不知道主机名的客户自定义域名
+func (m *Manager) EnableOnDemandForHosts(hosts ...string) error {
+	return m.EnableOnDemandForHostsContext(context.Background(), hosts...)
+}
+
+// EnableOnDemandForHostsContext 允许为指定主机名按需申请证书（支持 context 取消和超时）
+func (m *Manager) EnableOnDemandForHostsContext(ctx context.Context, hosts ...string) error {
+	ctx, unlock, err := m.client.Lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	policies, err := m.getPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range policies {
+		if sameSubjects(existing.Subjects, hosts) || (len(hosts) == 0 && len(existing.Subjects) == 0) {
+			return m.configManager.SetConfigPathContext(ctx, fmt.Sprintf("%s/%d/on_demand", PoliciesPath, i), true)
+		}
+	}
+
+	return m.insertPolicy(ctx, policies, types.TLSAutomationPolicy{
+		Subjects: append([]string(nil), hosts...),
+		OnDemand: true,
+	})
+}
+
+// parseAskURL 校验授权端点 URL
+func parseAskURL(askURL string) (*url.URL, error) {
+	u, err := url.Parse(askURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("无效的按需 TLS 授权端点: %s", askURL)
+	}
+	return u, nil
+}
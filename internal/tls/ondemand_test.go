@@ -0,0 +1,58 @@
+package tls
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/pkg/types"
+)
+
+func TestEnableOnDemandTLS(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit types.OnDemandRateLimit
+		want      interface{}
+	}{
+		{"no rate limit", types.OnDemandRateLimit{}, nil},
+		{"rate limit", types.OnDemandRateLimit{Interval: types.Duration(time.Minute), Burst: 10},
+			map[string]interface{}{"interval": "1m0s", "burst": float64(10)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, srv := newTestManager(t, "")
+			if err := m.EnableOnDemandTLS("http://localhost:9123/allowed", tt.rateLimit); err != nil {
+				t.Fatalf("配置按需 TLS 失败: %v", err)
+			}
+			if got := srv.Get(OnDemandPath + "/permission/endpoint"); got != "http://localhost:9123/allowed" {
+				t.Errorf("endpoint = %v", got)
+			}
+			if got := srv.Get(OnDemandPath + "/rate_limit"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rate_limit = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnableOnDemandTLSInvalidRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit types.OnDemandRateLimit
+	}{
+		{"negative interval", types.OnDemandRateLimit{Interval: types.Duration(-time.Second), Burst: 1}},
+		{"negative burst", types.OnDemandRateLimit{Interval: types.Duration(time.Second), Burst: -1}},
+		{"interval only", types.OnDemandRateLimit{Interval: types.Duration(time.Second)}},
+		{"burst only", types.OnDemandRateLimit{Burst: 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, srv := newTestManager(t, "")
+			if err := m.EnableOnDemandTLS("http://localhost:9123/allowed", tt.rateLimit); err == nil {
+				t.Fatal("期望返回错误")
+			}
+			if len(srv.Requests()) != 0 {
+				t.Errorf("校验失败时不应发送请求: %v", srv.Requests())
+			}
+		})
+	}
+}
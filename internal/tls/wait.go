@@ -0,0 +1,85 @@
+package tls
+
+import (
+	"context"
+	cryptotls "crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+// CertPollInterval 等待证书时两次检查之间的间隔
+const CertPollInterval = 2 * time.Second
+
+// certCheckTimeout 单次检查（建立连接和 TLS 握手）的超时时间
+// 避免 Caddy 接受连接后不响应时，一次握手阻塞到整个等待结束
+var certCheckTimeout = 10 * time.Second
+
+// WaitForCert 等待 Caddy 为指定主机名提供有效证书
+// 通过 TLS 握手检查 Caddy 实际提供的证书：证书覆盖该主机名且在有效期内即视为就绪
+// 配置了 SSH 时从远端主机发起握手；context 结束前证书仍未就绪则返回错误
+func (m *Manager) WaitForCert(ctx context.Context, hostname string) error {
+	if hostname == "" {
+		return fmt.Errorf("主机名不能为空")
+	}
+
+	// 未指定端口时使用 HTTPS 默认端口
+	addr := hostname
+	host, _, err := net.SplitHostPort(hostname)
+	if err != nil {
+		host, addr = hostname, net.JoinHostPort(hostname, "443")
+	}
+
+	ticker := time.NewTicker(CertPollInterval)
+	defer ticker.Stop()
+
+	for {
+		lastErr := m.checkCert(ctx, addr, host)
+		if lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待 %s 的证书失败: %w (最后一次检查: %v)", host, ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkCert 与 Caddy 进行一次 TLS 握手并验证其提供的证书，超过 certCheckTimeout 视为本次失败
+func (m *Manager) checkCert(ctx context.Context, addr, host string) error {
+	ctx, cancel := context.WithTimeout(ctx, certCheckTimeout)
+	defer cancel()
+
+	rawConn, err := m.client.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("连接 %s 失败: %w", addr, err)
+	}
+	defer rawConn.Close()
+
+	// 内部 CA 签发的证书不在系统信任库中，因此只检查主机名和有效期
+	conn := cryptotls.Client(rawConn, &cryptotls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("TLS 握手失败: %w", err)
+	}
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return fmt.Errorf("服务器未提供证书")
+	}
+
+	leaf := certs[0]
+	if err := leaf.VerifyHostname(host); err != nil {
+		return err
+	}
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return fmt.Errorf("证书不在有效期内: %s - %s", leaf.NotBefore, leaf.NotAfter)
+	}
+
+	return nil
+}
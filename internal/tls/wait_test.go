@@ -0,0 +1,74 @@
+package tls
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitForCert(t *testing.T) {
+	https := httptest.NewTLSServer(http.NotFoundHandler())
+	defer https.Close()
+
+	m, _ := newTestManager(t, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.WaitForCert(ctx, https.Listener.Addr().String()); err != nil {
+		t.Fatalf("等待证书失败: %v", err)
+	}
+}
+
+func TestWaitForCertWrongHost(t *testing.T) {
+	https := httptest.NewTLSServer(http.NotFoundHandler())
+	defer https.Close()
+	_, port, _ := net.SplitHostPort(https.Listener.Addr().String())
+
+	m, _ := newTestManager(t, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// httptest 的证书只覆盖 example.com、127.0.0.1 和 ::1
+	if err := m.WaitForCert(ctx, net.JoinHostPort("localhost", port)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, 期望在证书不覆盖主机名时超时", err)
+	}
+}
+
+func TestCheckCertHandshakeTimeout(t *testing.T) {
+	// 接受连接但从不响应握手
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	saved := certCheckTimeout
+	certCheckTimeout = 50 * time.Millisecond
+	defer func() { certCheckTimeout = saved }()
+
+	m, _ := newTestManager(t, "")
+	done := make(chan error, 1)
+	go func() { done <- m.checkCert(context.Background(), ln.Addr().String(), "127.0.0.1") }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("握手无响应时应返回错误")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("单次检查没有超时，一直阻塞在握手上")
+	}
+}
@@ -0,0 +1,175 @@
+package fastcaddy
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/OrbitDeploy/fastcaddy/internal/api"
+	"golang.org/x/crypto/ssh"
+)
+
+// Option 客户端配置选项 - 用于 NewWithOptions 定制与 Caddy 管理 API 的连接方式
+type Option func(*api.Options) error
+
+// WithHTTPClient 使用自定义的 HTTP 客户端访问 Caddy 管理 API
+// 可用于设置自签名管理证书、代理和超时等；客户端本身不会被修改
+// 与 WithSSHClient 或 WithUnixSocket 同时使用时，对应的拨号会安装到该客户端 Transport 的副本上，
+// 此时 Transport 必须是 *http.Transport（或为空），且其代理设置会被忽略
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *api.Options) error {
+		if c == nil {
+			return fmt.Errorf("HTTP 客户端不能为空")
+		}
+		o.HTTPClient = c
+		return nil
+	}
+}
+
+// WithBaseURL 设置 Caddy 管理 API 的地址，包括协议、主机和端口 (如 "http://10.0.0.5:2020")
+// 默认为 http://localhost:2019；与 WithSSHClient/WithSSHDialer 同时使用时，地址在远端主机上解析
+func WithBaseURL(baseURL string) Option {
+	return func(o *api.Options) error {
+		normalized, err := api.ParseBaseURL(baseURL)
+		if err != nil {
+			return err
+		}
+		o.BaseURL = normalized
+		return nil
+	}
+}
+
+// WithAdminAddr 设置 Caddy 管理 API 的 host:port (如 "127.0.0.1:2020")，使用 http 协议
+// 对应 Caddy 配置中的 admin.listen；需要 https 时使用 WithBaseURL
+func WithAdminAddr(addr string) Option {
+	return func(o *api.Options) error {
+		if addr == "" {
+			return fmt.Errorf("管理 API 地址不能为空")
+		}
+		normalized, err := api.ParseBaseURL("http://" + addr)
+		if err != nil {
+			return err
+		}
+		o.BaseURL = normalized
+		return nil
+	}
+}
+
+// WithUnixSocket 通过 Unix 套接字连接 Caddy 管理 API
+// 对应 Caddy 配置中的 admin unix//run/caddy-admin.sock
+// 与 WithSSHClient 同时使用时，套接字在远端主机上通过 SSH 连接拨号
+func WithUnixSocket(path string) Option {
+	return func(o *api.Options) error {
+		if path == "" {
+			return fmt.Errorf("unix 套接字路径不能为空")
+		}
+		o.SocketPath = path
+		return nil
+	}
+}
+
+// WithSSHClient 通过已建立的 SSH 连接访问 Caddy 管理 API
+// 所有连接都在远端主机上建立，管理地址按远端视角解析
+func WithSSHClient(client *ssh.Client) Option {
+	return func(o *api.Options) error {
+		if client == nil {
+			return fmt.Errorf("SSH 客户端不能为空")
+		}
+		o.SSHClient = client
+		return nil
+	}
+}
+
+// WithSSHDialer 通过按需建立的 SSH 连接访问 Caddy 管理 API
+// 首次请求时调用 dial 建立连接；检测到连接断开（网络中断、服务器重启）后自动重新拨号，
+// 并将失败的请求重试一次。同时设置时优先于 WithSSHClient
+func WithSSHDialer(dial func() (*ssh.Client, error)) Option {
+	return func(o *api.Options) error {
+		if dial == nil {
+			return fmt.Errorf("SSH 拨号函数不能为空")
+		}
+		o.SSHDialer = dial
+		return nil
+	}
+}
+
+// WithRetry 为瞬时失败（连接被拒绝、EOF、5xx 响应）启用指数退避重试
+// maxAttempts 为包括首次请求在内的最大尝试次数；4xx 响应不会重试
+// 向数组追加元素的 POST 等非幂等请求只在连接建立失败时重试，避免重复添加路由或上游
+// 重试会在请求 context 取消或超时后立即停止
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(o *api.Options) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf("重试次数必须大于 0: %d", maxAttempts)
+		}
+		if baseDelay < 0 {
+			return fmt.Errorf("重试间隔不能为负数: %s", baseDelay)
+		}
+		o.RetryAttempts = maxAttempts
+		o.RetryBaseDelay = baseDelay
+		return nil
+	}
+}
+
+// WithDryRun 启用预演模式
+// 所有修改配置的方法（AddRoute、AddUpstream、LoadConfigWithRollback 等）只做校验并记录将要执行的变更，
+// 不会真正应用；读取配置的请求照常发送。记录的变更可通过 PlannedChanges 获取
+func WithDryRun() Option {
+	return func(o *api.Options) error {
+		o.DryRun = true
+		return nil
+	}
+}
+
+// PlannedChange 预演模式下记录的一次配置变更
+type PlannedChange = api.PlannedChange
+
+// WithTimeout 为每个请求设置默认超时，覆盖建立连接（包括 SSH 通道）、发送请求到读完响应体的全过程
+// 只对没有截止时间的 context 生效：调用 ...Context 方法时传入带截止时间的 context 即可指定更短或更长的超时。
+// 使用内置 HTTP 客户端时会取消其 30 秒的整体超时，改由该值控制；自定义客户端的 Timeout 保持不变
+func WithTimeout(d time.Duration) Option {
+	return func(o *api.Options) error {
+		if d <= 0 {
+			return fmt.Errorf("请求超时时间必须大于 0: %s", d)
+		}
+		o.Timeout = d
+		return nil
+	}
+}
+
+// WithLogger 以 debug 级别记录每次发往管理 API 的请求（方法、URL、请求体）和响应（状态码、响应体）
+// 直接连接和通过 SSH 隧道时记录的内容相同；启用重试时每次尝试分别记录。
+// 私钥、API 令牌、EAB 密钥等敏感值会被替换为 [REDACTED]，请求体和响应体最多记录 64 KiB
+func WithLogger(l *slog.Logger) Option {
+	return func(o *api.Options) error {
+		if l == nil {
+			return fmt.Errorf("日志记录器不能为空")
+		}
+		o.Logger = l
+		return nil
+	}
+}
+
+// WithMaxIdleConns 设置与管理 API 之间保留的空闲连接数
+// 高频推送配置时调大该值可以避免反复建立连接；通过 SSH 访问时每个连接对应一个复用的 SSH 通道
+func WithMaxIdleConns(n int) Option {
+	return func(o *api.Options) error {
+		if n <= 0 {
+			return fmt.Errorf("空闲连接数必须大于 0: %d", n)
+		}
+		o.MaxIdleConns = n
+		return nil
+	}
+}
+
+// WithIdleTimeout 设置空闲连接在关闭前的保留时间
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *api.Options) error {
+		if d <= 0 {
+			return fmt.Errorf("空闲连接保留时间必须大于 0: %s", d)
+		}
+		o.IdleTimeout = d
+		return nil
+	}
+}
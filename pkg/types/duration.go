@@ -0,0 +1,36 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration Caddy 时长 - 序列化为 Go 时长字符串 (如 "30s")
+// 反序列化时同时接受字符串和纳秒整数，与 Caddy 自身的解析规则一致
+type Duration time.Duration
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return fmt.Errorf("无效的时长: %s", data)
+		}
+		*d = Duration(ns)
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("无效的时长 %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import "encoding/json"
+
+// plainHandler 与 Handler 字段相同但没有自定义序列化方法，避免递归调用
+type plainHandler Handler
+
+// MarshalJSON 实现 json.Marshaler 接口
+// static_response 的 headers 字段是普通的头部映射，与 reverse_proxy 的 headers 配置块结构不同，
+// 因此设置了 StaticHeaders 时以它作为 headers 字段输出
+func (h Handler) MarshalJSON() ([]byte, error) {
+	if h.StaticHeaders == nil {
+		return json.Marshal(plainHandler(h))
+	}
+	return json.Marshal(struct {
+		plainHandler
+		Headers map[string][]string `json:"headers,omitempty"`
+	}{plainHandler(h), h.StaticHeaders})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+// static_response 处理器的 headers 字段解析到 StaticHeaders
+func (h *Handler) UnmarshalJSON(data []byte) error {
+	var probe struct {
+		Handler string `json:"handler"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	if probe.Handler != "static_response" {
+		return json.Unmarshal(data, (*plainHandler)(h))
+	}
+
+	var static struct {
+		plainHandler
+		Headers map[string][]string `json:"headers,omitempty"`
+	}
+	if err := json.Unmarshal(data, &static); err != nil {
+		return err
+	}
+	*h = Handler(static.plainHandler)
+	h.StaticHeaders = static.Headers
+	return nil
+}
@@ -0,0 +1,49 @@
+package types
+
+import "strings"
+
+// Matcher 匹配条件 - 向匹配集合中添加一个条件
+// 通过 NewMatcherSet 组合多个条件，生成 Caddy 所需的 match 数组元素
+type Matcher func(*RouteMatch)
+
+// NewMatcherSet 将多个匹配条件组合为一个匹配集合，集合中的条件必须同时满足
+func NewMatcherSet(matchers ...Matcher) RouteMatch {
+	var set RouteMatch
+	for _, matcher := range matchers {
+		matcher(&set)
+	}
+	return set
+}
+
+// MatchHost 匹配请求的主机名 (如 "example.com", "*.example.com")
+func MatchHost(hosts ...string) Matcher {
+	return func(set *RouteMatch) {
+		set.Host = append(set.Host, hosts...)
+	}
+}
+
+// MatchPath 匹配请求路径，支持通配符 (如 "/api/*")
+func MatchPath(paths ...string) Matcher {
+	return func(set *RouteMatch) {
+		set.Path = append(set.Path, paths...)
+	}
+}
+
+// MatchHeader 匹配请求头，values 中任意一个值匹配即可
+func MatchHeader(name string, values ...string) Matcher {
+	return func(set *RouteMatch) {
+		if set.Header == nil {
+			set.Header = make(map[string][]string)
+		}
+		set.Header[name] = append(set.Header[name], values...)
+	}
+}
+
+// MatchMethod 匹配请求方法 (如 "GET", "POST")
+func MatchMethod(methods ...string) Matcher {
+	return func(set *RouteMatch) {
+		for _, method := range methods {
+			set.Method = append(set.Method, strings.ToUpper(method))
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RouteConfig 与 Caddy 路由结构一一对应的路由表示
+// 匹配集合和处理器保留为原始 JSON，任何模块的配置都不会因类型定义不全而丢失；
+// 未识别的顶层字段保存在 Extra 中，序列化结果与 Caddy 返回的 JSON 一致
+type RouteConfig struct {
+	ID          string                     // 路由唯一标识符 (@id)
+	Group       string                     // 路由组，同组路由中只执行第一个匹配的路由
+	MatcherSets []json.RawMessage          // 匹配集合 (match)，集合之间为 OR 关系
+	Handle      []json.RawMessage          // 处理器列表，每个元素的 "handler" 字段为处理器类型
+	Terminal    bool                       // 是否为终端路由
+	Extra       map[string]json.RawMessage // 未识别的顶层字段
+}
+
+// RouteDefinition 可以添加到 Caddy 的路由 - 类型化的 Route 或保留原始 JSON 的 RouteConfig
+// AddRoute、AddRoutes、AddRouteToServer 和 UpsertRoute 都接受这两种类型
+type RouteDefinition interface {
+	ToRouteConfig() (RouteConfig, error)
+}
+
+// ToRouteConfig 实现 RouteDefinition 接口，转换为 RouteConfig
+func (r Route) ToRouteConfig() (RouteConfig, error) {
+	return NewRouteConfig(r)
+}
+
+// ToRouteConfig 实现 RouteDefinition 接口，返回自身
+func (r RouteConfig) ToRouteConfig() (RouteConfig, error) {
+	return r, nil
+}
+
+// NewRouteConfig 将类型化的 Route 转换为 RouteConfig
+func NewRouteConfig(route Route) (RouteConfig, error) {
+	data, err := json.Marshal(route)
+	if err != nil {
+		return RouteConfig{}, fmt.Errorf("序列化路由失败: %w", err)
+	}
+	var config RouteConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return RouteConfig{}, err
+	}
+	return config, nil
+}
+
+// HandlerNames 返回处理器列表中每个处理器的类型 (如 "reverse_proxy")
+func (r RouteConfig) HandlerNames() []string {
+	names := make([]string, 0, len(r.Handle))
+	for _, raw := range r.Handle {
+		var handler struct {
+			Handler string `json:"handler"`
+		}
+		_ = json.Unmarshal(raw, &handler)
+		names = append(names, handler.Handler)
+	}
+	return names
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+// 与 Caddy 一样按键名排序输出，空字段省略
+func (r RouteConfig) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(r.Extra)+5)
+	for key, value := range r.Extra {
+		fields[key] = value
+	}
+	if r.ID != "" {
+		fields["@id"] = r.ID
+	}
+	if r.Group != "" {
+		fields["group"] = r.Group
+	}
+	if len(r.MatcherSets) > 0 {
+		fields["match"] = r.MatcherSets
+	}
+	if len(r.Handle) > 0 {
+		fields["handle"] = r.Handle
+	}
+	if r.Terminal {
+		fields["terminal"] = true
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+func (r *RouteConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("解析路由失败: %w", err)
+	}
+
+	var route RouteConfig
+	known := []struct {
+		key    string
+		target interface{}
+	}{
+		{"@id", &route.ID},
+		{"group", &route.Group},
+		{"match", &route.MatcherSets},
+		{"handle", &route.Handle},
+		{"terminal", &route.Terminal},
+	}
+	for _, field := range known {
+		raw, ok := fields[field.key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, field.target); err != nil {
+			return fmt.Errorf("解析路由字段 %s 失败: %w", field.key, err)
+		}
+		delete(fields, field.key)
+	}
+	if len(fields) > 0 {
+		route.Extra = fields
+	}
+
+	*r = route
+	return nil
+}
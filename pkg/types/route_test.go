@@ -0,0 +1,73 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRouteConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"empty", `{}`},
+		{"reverse proxy", `{"@id":"api","handle":[{"handler":"reverse_proxy","upstreams":[{"dial":"localhost:8080"}]}],"match":[{"host":["api.example.com"]}],"terminal":true}`},
+		{"group and unknown fields", `{"group":"g1","handle":[{"handler":"static_response","status_code":302}],"x_custom":{"a":[1,2]}}`},
+		{"unknown handler fields", `{"handle":[{"handler":"rate_limit","rate_limits":{"default":{"key":"{remote_host}","max_events":10,"window":"1m"}}}]}`},
+		{"nested subroute", `{"handle":[{"handler":"subroute","routes":[{"@id":"inner","handle":[{"handler":"file_server","root":"/srv"}]}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var route RouteConfig
+			if err := json.Unmarshal([]byte(tt.json), &route); err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			got, err := json.Marshal(route)
+			if err != nil {
+				t.Fatalf("序列化失败: %v", err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("往返结果不一致:\n 得到 %s\n 期望 %s", got, tt.json)
+			}
+		})
+	}
+}
+
+func TestRouteConfigUnmarshalInvalid(t *testing.T) {
+	for _, input := range []string{`[]`, `{"terminal":"yes"}`, `{"handle":{}}`} {
+		var route RouteConfig
+		if err := json.Unmarshal([]byte(input), &route); err == nil {
+			t.Errorf("%s: 期望解析失败", input)
+		}
+	}
+}
+
+func TestNewRouteConfig(t *testing.T) {
+	route := Route{
+		ID:       "app",
+		Match:    []RouteMatch{{Host: []string{"app.example.com"}}},
+		Handle:   []Handler{{Handler: "reverse_proxy", Upstreams: []Upstream{{Dial: "localhost:8080"}}}},
+		Terminal: true,
+	}
+	config, err := NewRouteConfig(route)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ID != "app" || !config.Terminal || len(config.MatcherSets) != 1 {
+		t.Errorf("转换结果 = %+v", config)
+	}
+	if got, want := config.HandlerNames(), []string{"reverse_proxy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HandlerNames() = %v, 期望 %v", got, want)
+	}
+
+	// 转换前后发送给 Caddy 的 JSON 相同
+	want, _ := json.Marshal(route)
+	var wantFields, gotFields map[string]interface{}
+	got, _ := json.Marshal(config)
+	json.Unmarshal(want, &wantFields)
+	json.Unmarshal(got, &gotFields)
+	if !reflect.DeepEqual(gotFields, wantFields) {
+		t.Errorf("JSON 不一致:\n 得到 %s\n 期望 %s", got, want)
+	}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // Caddy 配置结构 - 表示整个 Caddy 配置的顶层结构
 type CaddyConfig struct {
 	Apps map[string]interface{} `json:"apps"`
@@ -7,23 +9,179 @@ type CaddyConfig struct {
 
 // 路由规则结构 - 定义单个路由规则
 type Route struct {
-	ID       string        `json:"@id,omitempty"`       // 路由唯一标识符
-	Match    []RouteMatch  `json:"match"`               // 匹配条件列表
-	Handle   []Handler     `json:"handle"`              // 处理器列表
-	Terminal bool          `json:"terminal"`            // 是否为终端路由
+	ID       string       `json:"@id,omitempty"` // 路由唯一标识符
+	Match    []RouteMatch `json:"match"`         // 匹配条件列表
+	Handle   []Handler    `json:"handle"`        // 处理器列表
+	Terminal bool         `json:"terminal"`      // 是否为终端路由
 }
 
 // 路由匹配规则 - 定义路由匹配条件
+// 同一个匹配集合中的不同条件之间为 AND 关系，同一条件中的多个值之间为 OR 关系
 type RouteMatch struct {
-	Host []string `json:"host,omitempty"` // 主机名匹配列表
-	Path []string `json:"path,omitempty"` // 路径匹配列表
+	Host   []string            `json:"host,omitempty"`   // 主机名匹配列表
+	Path   []string            `json:"path,omitempty"`   // 路径匹配列表
+	Header map[string][]string `json:"header,omitempty"` // 请求头匹配 (字段名 -> 可选值列表)
+	Method []string            `json:"method,omitempty"` // 请求方法匹配列表
+	File   *FileMatcher        `json:"file,omitempty"`   // 文件存在性匹配
+}
+
+// 文件匹配器 - 按顺序检查文件是否存在，对应 Caddy 的 file 匹配器
+type FileMatcher struct {
+	Root     string   `json:"root,omitempty"`      // 查找文件的根目录
+	TryFiles []string `json:"try_files,omitempty"` // 依次尝试的文件路径，支持占位符
 }
 
 // 处理器结构 - 定义路由处理逻辑
 type Handler struct {
-	Handler   string     `json:"handler"`              // 处理器类型 (如 "reverse_proxy", "subroute")
-	Upstreams []Upstream `json:"upstreams,omitempty"`  // 上游服务器列表 (用于反向代理)
-	Routes    []Route    `json:"routes,omitempty"`     // 子路由列表 (用于子路由处理器)
+	Handler       string         `json:"handler"`                  // 处理器类型 (如 "reverse_proxy", "subroute")
+	Upstreams     []Upstream     `json:"upstreams,omitempty"`      // 上游服务器列表 (用于反向代理)
+	Headers       *HeadersConfig `json:"headers,omitempty"`        // 请求/响应头操作 (用于反向代理)
+	FlushInterval Duration       `json:"flush_interval,omitempty"` // 响应刷新间隔，负值 (如 -1) 表示立即刷新 (用于反向代理)
+	LoadBalancing *LoadBalancing `json:"load_balancing,omitempty"` // 负载均衡配置 (用于反向代理)
+	HealthChecks  *HealthChecks  `json:"health_checks,omitempty"`  // 健康检查配置 (用于反向代理)
+	Transport     *HTTPTransport `json:"transport,omitempty"`      // 与上游通信的传输层配置 (用于反向代理)
+	Routes        []Route        `json:"routes,omitempty"`         // 子路由列表 (用于子路由处理器)
+
+	Encodings     map[string]struct{} `json:"encodings,omitempty"`      // 启用的压缩算法 (用于 encode 处理器)
+	Prefer        []string            `json:"prefer,omitempty"`         // 客户端同时支持时的算法优先顺序 (用于 encode 处理器)
+	MinimumLength int                 `json:"minimum_length,omitempty"` // 启用压缩的最小响应长度 (用于 encode 处理器)
+
+	Root       string      `json:"root,omitempty"`        // 站点根目录 (用于 file_server 处理器)
+	Browse     *FileBrowse `json:"browse,omitempty"`      // 启用目录浏览 (用于 file_server 处理器)
+	IndexNames []string    `json:"index_names,omitempty"` // 目录索引文件名 (用于 file_server 处理器)
+	URI        string      `json:"uri,omitempty"`         // 重写后的 URI (用于 rewrite 处理器)
+
+	Providers *AuthProviders `json:"providers,omitempty"` // 认证提供者 (用于 authentication 处理器)
+
+	RateLimits map[string]RateLimitZone `json:"rate_limits,omitempty"` // 限流区域 (用于 rate_limit 处理器)
+
+	MaxSize int64 `json:"max_size,omitempty"` // 请求体大小上限，单位为字节 (用于 request_body 处理器)
+
+	Response *RespHeaderOps `json:"response,omitempty"` // 响应头操作 (用于 headers 处理器)
+
+	StatusCode    int                 `json:"status_code,omitempty"` // 响应状态码 (用于 static_response 处理器)
+	Body          string              `json:"body,omitempty"`        // 响应体 (用于 static_response 处理器)
+	StaticHeaders map[string][]string `json:"-"`                     // 响应头，序列化为 static_response 的 headers 字段
+}
+
+// 负载均衡配置 - 对应 reverse_proxy 的 load_balancing 配置块
+type LoadBalancing struct {
+	SelectionPolicy *SelectionPolicy `json:"selection_policy,omitempty"` // 上游选择策略
+	TryDuration     Duration         `json:"try_duration,omitempty"`     // 选择可用上游的最长重试时间
+	TryInterval     Duration         `json:"try_interval,omitempty"`     // 两次选择上游之间的等待时间
+}
+
+// 上游选择策略 - 定义负载均衡算法
+type SelectionPolicy struct {
+	Policy string `json:"policy"` // 策略名称 (如 "round_robin", "least_conn", "ip_hash", "random")
+}
+
+// 健康检查配置 - 对应 reverse_proxy 的 health_checks 配置块
+type HealthChecks struct {
+	Active  *ActiveHealthChecks  `json:"active,omitempty"`  // 主动健康检查
+	Passive *PassiveHealthChecks `json:"passive,omitempty"` // 被动健康检查
+}
+
+// 主动健康检查 - 定期请求上游的检查地址
+type ActiveHealthChecks struct {
+	URI          string   `json:"uri,omitempty"`           // 检查请求的路径 (如 "/health")
+	Interval     Duration `json:"interval,omitempty"`      // 检查间隔
+	Timeout      Duration `json:"timeout,omitempty"`       // 单次检查超时时间
+	ExpectStatus int      `json:"expect_status,omitempty"` // 期望的响应状态码
+}
+
+// 被动健康检查 - 根据实际代理请求的结果判断上游健康状态
+type PassiveHealthChecks struct {
+	FailDuration    Duration `json:"fail_duration,omitempty"`    // 失败记录的保留时间
+	MaxFails        int      `json:"max_fails,omitempty"`        // 在保留时间内允许的最大失败次数
+	UnhealthyStatus []int    `json:"unhealthy_status,omitempty"` // 视为失败的响应状态码
+}
+
+// 响应压缩选项 - 用于生成 encode 处理器
+type EncodeOptions struct {
+	Encodings     []string // 压缩算法 ("gzip", "zstd")，按优先顺序排列，为空时同时启用两者
+	MinimumLength int      // 启用压缩的最小响应字节数，0 表示使用 Caddy 默认值
+}
+
+// 文件服务选项 - 用于生成 file_server 路由
+type FileServerOptions struct {
+	ID          string   // 路由 @id，为空时不设置
+	Browse      bool     // 没有索引文件时列出目录内容
+	IndexNames  []string // 目录索引文件名，为空时使用 Caddy 默认值 (index.html, index.txt)
+	SPAFallback string   // 单页应用回退文件 (如 "/index.html")，请求的文件不存在时改为返回该文件
+}
+
+// 目录浏览配置 - 对应 file_server 的 browse 配置块
+type FileBrowse struct {
+	TemplateFile string `json:"template_file,omitempty"` // 自定义目录列表模板
+}
+
+// 认证提供者 - 对应 authentication 处理器的 providers 配置块
+type AuthProviders struct {
+	HTTPBasic *HTTPBasicAuth `json:"http_basic,omitempty"` // HTTP Basic 认证
+}
+
+// HTTP Basic 认证配置
+type HTTPBasicAuth struct {
+	Accounts []BasicAuthAccount `json:"accounts"`        // 允许访问的账号
+	Hash     *AuthHash          `json:"hash,omitempty"`  // 密码哈希算法
+	Realm    string             `json:"realm,omitempty"` // 认证域名称
+}
+
+// Basic 认证账号 - 密码为哈希值而非明文
+type BasicAuthAccount struct {
+	Username string `json:"username"` // 用户名
+	Password string `json:"password"` // 密码哈希
+}
+
+// 密码哈希算法配置
+type AuthHash struct {
+	Algorithm string `json:"algorithm"` // 哈希算法 (如 "bcrypt")
+}
+
+// 限流选项 - 用于生成 rate_limit 处理器
+type RateLimitOptions struct {
+	Zone   string        // 限流区域名称，为空时使用 "default"
+	Header string        // 按该请求头的值区分客户端，为空时按客户端 IP
+	Events int           // 每个窗口内允许的最大请求数
+	Window time.Duration // 窗口时长
+}
+
+// 限流区域 - 对应 rate_limit 处理器中的单个区域配置
+type RateLimitZone struct {
+	Key       string   `json:"key"`        // 区分客户端的键，支持占位符
+	Window    Duration `json:"window"`     // 窗口时长
+	MaxEvents int      `json:"max_events"` // 窗口内允许的最大请求数
+}
+
+// HTTP 传输层配置 - 对应 reverse_proxy 的 http transport 配置块
+// 超时为 0 时省略，使用 Caddy 默认值
+type HTTPTransport struct {
+	Protocol              string   `json:"protocol"`                          // 传输协议，固定为 "http"
+	DialTimeout           Duration `json:"dial_timeout,omitempty"`            // 连接上游的超时时间
+	ReadTimeout           Duration `json:"read_timeout,omitempty"`            // 读取上游响应的超时时间
+	WriteTimeout          Duration `json:"write_timeout,omitempty"`           // 向上游写入请求的超时时间
+	ResponseHeaderTimeout Duration `json:"response_header_timeout,omitempty"` // 等待上游响应头的超时时间
+	Versions              []string `json:"versions,omitempty"`                // 与上游通信允许的 HTTP 版本 (如 "1.1", "2", "h2c")
+}
+
+// 头部操作配置 - 对应 Caddy 的 headers 配置块
+type HeadersConfig struct {
+	Request  *HeaderOps     `json:"request,omitempty"`  // 请求头操作
+	Response *RespHeaderOps `json:"response,omitempty"` // 响应头操作
+}
+
+// 头部操作 - 定义添加、设置和删除的头部字段
+type HeaderOps struct {
+	Add    map[string][]string `json:"add,omitempty"`    // 追加的头部字段
+	Set    map[string][]string `json:"set,omitempty"`    // 覆盖设置的头部字段
+	Delete []string            `json:"delete,omitempty"` // 删除的头部字段
+}
+
+// 响应头操作 - 在头部操作基础上支持延迟执行
+type RespHeaderOps struct {
+	HeaderOps
+	Deferred bool `json:"deferred,omitempty"` // 是否在响应写出前才执行
 }
 
 // 上游服务器 - 定义反向代理的目标服务器
@@ -31,6 +189,14 @@ type Upstream struct {
 	Dial string `json:"dial"` // 目标服务器地址 (如 "localhost:8080")
 }
 
+// 上游服务器状态 - 对应管理 API /reverse_proxy/upstreams 返回的一项
+type UpstreamStatus struct {
+	Address     string `json:"address"`      // 上游服务器地址
+	NumRequests int    `json:"num_requests"` // 当前正在处理的请求数
+	Fails       int    `json:"fails"`        // 被动健康检查记录的近期失败次数
+	Healthy     bool   `json:"-"`            // 是否健康，Caddy 不直接返回，按近期没有失败计算
+}
+
 // HTTP 服务器配置 - 定义 HTTP 服务器的配置
 type HTTPServer struct {
 	Listen    []string `json:"listen"`              // 监听地址列表
@@ -38,24 +204,126 @@ type HTTPServer struct {
 	Protocols []string `json:"protocols,omitempty"` // 支持的协议列表
 }
 
+// 自动 HTTPS 选项 - 对应服务器的 automatic_https 配置块
+type AutoHTTPSOptions struct {
+	Disable             bool     `json:"disable,omitempty"`              // 完全禁用自动 HTTPS
+	DisableRedirects    bool     `json:"disable_redirects,omitempty"`    // 禁用 HTTP 到 HTTPS 的重定向
+	DisableCertificates bool     `json:"disable_certificates,omitempty"` // 禁用自动申请证书
+	Skip                []string `json:"skip,omitempty"`                 // 不启用自动 HTTPS 的主机名
+	SkipCertificates    []string `json:"skip_certificates,omitempty"`    // 不自动申请证书但仍启用重定向的主机名
+}
+
+// 访问日志选项 - 用于 EnableAccessLog，将服务器的访问日志写入按大小滚动的文件
+type AccessLogOptions struct {
+	LoggerName   string // 日志名称，为空时使用服务器名称
+	Filename     string // 日志文件的绝对路径
+	Format       string // 日志格式: json 或 console，为空时使用 json
+	RollSizeMB   int    // 单个日志文件的最大大小 (MB)，为 0 时使用 Caddy 默认值 100
+	RollKeep     int    // 保留的历史日志文件数，为 0 时使用 Caddy 默认值 10
+	RollKeepDays int    // 历史日志文件的保留天数，为 0 时使用 Caddy 默认值 90
+}
+
+// 服务器日志配置 - 对应服务器的 logs 配置块
+type ServerLogConfig struct {
+	DefaultLoggerName string `json:"default_logger_name,omitempty"` // 访问日志写入的日志名称
+}
+
+// 自定义日志 - 对应 logging/logs 中的一项
+type CustomLog struct {
+	Writer  *LogWriter  `json:"writer,omitempty"`  // 日志输出
+	Encoder *LogEncoder `json:"encoder,omitempty"` // 日志编码格式
+	Level   string      `json:"level,omitempty"`   // 最低日志级别
+	Include []string    `json:"include,omitempty"` // 只记录这些日志记录器的条目
+	Exclude []string    `json:"exclude,omitempty"` // 不记录这些日志记录器的条目
+}
+
+// 日志输出 - 对应 file 或 net 输出模块
+type LogWriter struct {
+	Output       string `json:"output"`                   // 输出模块: "file" 或 "net"
+	Filename     string `json:"filename,omitempty"`       // 日志文件路径 (用于 file)
+	RollSizeMB   int    `json:"roll_size_mb,omitempty"`   // 单个日志文件的最大大小 (MB) (用于 file)
+	RollKeep     int    `json:"roll_keep,omitempty"`      // 保留的历史日志文件数 (用于 file)
+	RollKeepDays int    `json:"roll_keep_days,omitempty"` // 历史日志文件的保留天数 (用于 file)
+	Address      string `json:"address,omitempty"`        // 日志发送到的网络地址，如 "tcp/127.0.0.1:9000" (用于 net)
+	SoftStart    bool   `json:"soft_start,omitempty"`     // 启动时无法连接也不使配置加载失败 (用于 net)
+}
+
+// 日志编码格式 - 对应 json 或 console 编码模块
+type LogEncoder struct {
+	Format string `json:"format"` // 编码格式
+}
+
+// TLS 连接策略 - 对应服务器 tls_connection_policies 中的一项
+type TLSConnectionPolicy struct {
+	Match                *ConnectionMatch      `json:"match,omitempty"`                 // 策略适用的连接，为空时适用于所有连接
+	ClientAuthentication *ClientAuthentication `json:"client_authentication,omitempty"` // 客户端证书校验
+}
+
+// TLS 连接匹配条件 - 按客户端握手时的 SNI 匹配
+type ConnectionMatch struct {
+	SNI []string `json:"sni,omitempty"` // 匹配的主机名
+}
+
+// 客户端证书校验 - 对应连接策略的 client_authentication 配置块
+type ClientAuthentication struct {
+	TrustedCACerts []string `json:"trusted_ca_certs,omitempty"` // 信任的 CA 证书，base64 编码的 DER
+	Mode           string   `json:"mode,omitempty"`             // 校验模式 (如 "require_and_verify")
+}
+
 // TLS 自动化策略 - 定义 TLS 证书自动化策略
 type TLSAutomationPolicy struct {
-	Issuers []TLSIssuer `json:"issuers"` // 证书颁发者列表
+	Subjects []string    `json:"subjects,omitempty"`  // 适用的主机名，为空时适用于所有主机名
+	Issuers  []TLSIssuer `json:"issuers,omitempty"`   // 证书颁发者列表，为空时使用默认颁发者
+	OnDemand bool        `json:"on_demand,omitempty"` // 在首次握手时按需申请证书
+}
+
+// 按需 TLS 配置 - 对应 tls/automation/on_demand 配置块
+type OnDemandConfig struct {
+	Permission *OnDemandPermission `json:"permission,omitempty"` // 申请证书前的授权检查
+	RateLimit  *OnDemandRateLimit  `json:"rate_limit,omitempty"` // 证书申请频率限制
+}
+
+// 按需 TLS 授权检查 - Caddy 申请证书前请求 endpoint?domain=<主机名>，返回 2xx 才允许申请
+type OnDemandPermission struct {
+	Module   string `json:"module"`   // 授权模块，固定为 "http"
+	Endpoint string `json:"endpoint"` // 授权检查端点 (ask URL)
+}
+
+// 按需 TLS 频率限制 - 每个 Interval 内最多申请 Burst 个证书
+type OnDemandRateLimit struct {
+	Interval Duration `json:"interval,omitempty"` // 限制窗口
+	Burst    int      `json:"burst,omitempty"`    // 窗口内允许的最大申请数
 }
 
 // TLS 证书颁发者 - 定义证书颁发者配置
 type TLSIssuer struct {
-	Module     string                 `json:"module"`               // 颁发者模块类型 (如 "acme", "internal")
-	Challenges map[string]interface{} `json:"challenges,omitempty"` // ACME 挑战配置
+	Module          string                 `json:"module"`                     // 颁发者模块类型 (如 "acme", "internal")
+	CA              string                 `json:"ca,omitempty"`               // ACME 目录 URL，为空时使用 Let's Encrypt
+	Email           string                 `json:"email,omitempty"`            // ACME 账号邮箱
+	ExternalAccount *ExternalAccount       `json:"external_account,omitempty"` // 外部账号绑定 (EAB)，如 ZeroSSL
+	Challenges      map[string]interface{} `json:"challenges,omitempty"`       // ACME 挑战配置
+}
+
+// ACME 外部账号绑定 - CA 提供的 EAB 凭据
+type ExternalAccount struct {
+	KeyID  string `json:"key_id"`  // EAB 密钥 ID
+	MACKey string `json:"mac_key"` // Base64 编码的 EAB HMAC 密钥
 }
 
 // ACME DNS 提供商配置 - 定义 DNS 挑战提供商
 type ACMEProvider struct {
-	Name     string `json:"name"`     // 提供商名称 (如 "cloudflare")
+	Name     string `json:"name"`      // 提供商名称 (如 "cloudflare")
 	APIToken string `json:"api_token"` // API 令牌
 }
 
+// PEM 证书 - 对应 tls 应用 certificates/load_pem 中的一项
+type PEMCertificate struct {
+	Certificate string   `json:"certificate"`    // PEM 编码的证书链
+	Key         string   `json:"key"`            // PEM 编码的私钥
+	Tags        []string `json:"tags,omitempty"` // 证书标签，可在连接策略中按标签选择证书
+}
+
 // PKI 配置 - 定义 PKI 证书颁发机构配置
 type PKIConfig struct {
 	InstallTrust bool `json:"install_trust"` // 是否安装信任根证书
-}
\ No newline at end of file
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHandlerFlushInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Duration
+	}{
+		{"caddy integer", `{"handler":"reverse_proxy","flush_interval":-1}`, -1},
+		{"duration string", `{"handler":"reverse_proxy","flush_interval":"100ms"}`, Duration(100 * time.Millisecond)},
+		{"negative string", `{"handler":"reverse_proxy","flush_interval":"-1ns"}`, -1},
+		{"unset", `{"handler":"reverse_proxy"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handler Handler
+			if err := json.Unmarshal([]byte(tt.json), &handler); err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			if handler.FlushInterval != tt.want {
+				t.Errorf("FlushInterval = %v, 期望 %v", time.Duration(handler.FlushInterval), time.Duration(tt.want))
+			}
+
+			// 序列化后再解析应得到相同的值，未设置时省略该字段
+			data, err := json.Marshal(handler)
+			if err != nil {
+				t.Fatalf("序列化失败: %v", err)
+			}
+			var decoded Handler
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("重新解析 %s 失败: %v", data, err)
+			}
+			if decoded.FlushInterval != tt.want {
+				t.Errorf("往返后 FlushInterval = %v, 期望 %v", time.Duration(decoded.FlushInterval), time.Duration(tt.want))
+			}
+		})
+	}
+}